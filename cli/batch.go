@@ -1,10 +1,19 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"gpt/openai"
+	"gpt/psy"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -16,9 +25,13 @@ type BatchCommand struct {
 	rootCmd    *cobra.Command
 	baseCmd    *cobra.Command
 	createCmd  *cobra.Command
+	submitCmd  *cobra.Command
 	readCmd    *cobra.Command
 	monitorCmd *cobra.Command
 	cancelCmd  *cobra.Command
+	retryCmd   *cobra.Command
+	watchCmd   *cobra.Command
+	exportCmd  *cobra.Command
 	listCmd    *cobra.Command
 	raw        bool
 }
@@ -39,15 +52,37 @@ func NewBatchCommand(apiClient *openai.Client, root *cobra.Command) *BatchComman
 	c.rootCmd.AddCommand(c.baseCmd)
 
 	// Create Command
+	// Example: gpt batch create file-abc123 --endpoint /v1/embeddings
 	c.createCmd = &cobra.Command{
 		Use:   "create <inputFileID>",
 		Short: "Create a new batch operation",
-		Long:  "Create a new Chat Completion batch using the specified input file ID.",
-		Args:  cobra.ExactArgs(1),
-		RunE:  c.create,
+		Long: "Create a new batch using the specified input file ID. The endpoint may be\n" +
+			strings.Join(openai.BatchEndpoints, ", ") + ".",
+		Args: cobra.ExactArgs(1),
+		RunE: c.create,
 	}
+	c.createCmd.Flags().StringP("endpoint", "e", openai.EndpointChat, "API endpoint of the batched requests")
+	c.createCmd.Flags().StringP("completion-window", "c", "24h", "Completion window")
 	c.baseCmd.AddCommand(c.createCmd)
 
+	// Submit Command
+	// Example: gpt batch submit requests.jsonl -M project=essays -w 30
+	c.submitCmd = &cobra.Command{
+		Use:   "submit <file.jsonl>",
+		Short: "Validate, upload, and submit a local batch input file",
+		Long: "Validate a local JSONL batch input file, reporting every invalid line, then upload it and\n" +
+			"create a batch operation for the endpoint of its requests. The file is checked for JSON\n" +
+			"syntax, unique custom IDs, a consistent endpoint and model, request bodies that are valid\n" +
+			"for the endpoint, and the batch input limits (50,000 requests and 200 MB).",
+		Args: cobra.ExactArgs(1),
+		RunE: c.submit,
+	}
+	c.submitCmd.Flags().StringToStringP("metadata", "M", nil, "Metadata key=value pairs (optional)")
+	c.submitCmd.Flags().StringP("completion-window", "c", "24h", "Completion window")
+	c.submitCmd.Flags().BoolP("validate", "n", false, "Validate the file only?")
+	c.submitCmd.Flags().IntP("wait", "w", 0, "Wait interval (seconds) for completion (0 to exit)")
+	c.baseCmd.AddCommand(c.submitCmd)
+
 	// Read Command
 	c.readCmd = &cobra.Command{
 		Use:   "read <batchID> [batchID]...",
@@ -60,9 +95,9 @@ func NewBatchCommand(apiClient *openai.Client, root *cobra.Command) *BatchComman
 
 	// Monitor Command
 	c.monitorCmd = &cobra.Command{
-		Use:   "monitor <batchID>",
+		Use:   "monitor <batchID|runID>",
 		Short: "Monitor specified batch operation",
-		Long:  "Monitor the progress of a batch operation, specified by ID.",
+		Long:  "Monitor the progress of a batch operation, or of all the batches in a run, specified by ID.",
 		Args:  cobra.MinimumNArgs(1),
 		RunE:  c.monitor,
 	}
@@ -79,6 +114,60 @@ func NewBatchCommand(apiClient *openai.Client, root *cobra.Command) *BatchComman
 	}
 	c.baseCmd.AddCommand(c.cancelCmd)
 
+	// Retry Command
+	// Example: gpt batch retry batch_abc123
+	c.retryCmd = &cobra.Command{
+		Use:   "retry <batchID|runID>",
+		Short: "Retry the failed requests of a batch operation",
+		Long: "Resubmit the failed or missing (e.g. expired) requests of a completed batch operation,\n" +
+			"or of all the batches in a run, as a follow-up batch. The follow-up batch is linked to\n" +
+			"the run by metadata, so \"chat results\" merges its results into the same output file.\n" +
+			"If the follow-up batches can't all be submitted, those already created are cancelled.",
+		Args: cobra.ExactArgs(1),
+		RunE: c.retry,
+	}
+	c.baseCmd.AddCommand(c.retryCmd)
+
+	// Watch Command
+	// Example: gpt batch watch --jobs --keep
+	c.watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Watch batch operations, processing their results as they complete",
+		Long: "Watch all the batch operations in progress (or only those in the local job registry),\n" +
+			"logging status transitions. When every batch in a run is done, its results are processed\n" +
+			"as with the \"chat results\" command, retrying on later polls if that fails. Polling backs\n" +
+			"off while nothing changes. The watch ends when no batches remain in progress, and no\n" +
+			"results remain to be processed, unless told to keep watching.",
+		Args: cobra.NoArgs,
+		RunE: c.watch,
+	}
+	c.watchCmd.Flags().IntP("wait", "w", 30, "Initial wait interval (seconds)")
+	c.watchCmd.Flags().IntP("max-wait", "m", 600, "Maximum wait interval (seconds)")
+	c.watchCmd.Flags().BoolP("jobs", "j", false, "Watch only batches in the local job registry?")
+	c.watchCmd.Flags().BoolP("keep", "k", false, "Keep watching when no batches remain?")
+	c.baseCmd.AddCommand(c.watchCmd)
+
+	// Export Command
+	// Example: gpt batch export batch_abc123 -o results.csv --join answers.csv --on chatID
+	c.exportCmd = &cobra.Command{
+		Use:   "export <batchID|runID>",
+		Short: "Export the results of a batch operation",
+		Long: "Download the output and error files of a batch operation, or of all the batches in a run,\n" +
+			"and flatten them into a CSV (or .jsonl) file with one row per custom_id, including the\n" +
+			"status code, completion, finish reason, token usage, and any error. Unlike \"chat results\",\n" +
+			"it doesn't need the output file named in the batch metadata. To rebuild the full results\n" +
+			"table, join the completions and scores to an answers CSV file (e.g. the incomplete results\n" +
+			"file saved by \"chat batch\") on its chat ID field.",
+		Args: cobra.ExactArgs(1),
+		RunE: c.export,
+	}
+	c.exportCmd.Flags().StringP("output", "o", "", "Output file (.csv or .jsonl; default <runID>.csv)")
+	c.exportCmd.Flags().StringP("join", "j", "", "Answers CSV file to join the results to (optional)")
+	c.exportCmd.Flags().String("on", "chatID", "Join field name (custom_id values)")
+	c.exportCmd.Flags().String("score-field", "", "Score field name (default: from metadata, or score)")
+	c.exportCmd.Flags().String("score-select", "", "Score selection: first | last | all | none (default: from metadata, or last)")
+	c.baseCmd.AddCommand(c.exportCmd)
+
 	// List Command
 	c.listCmd = &cobra.Command{
 		Use:   "list",
@@ -97,6 +186,14 @@ func NewBatchCommand(apiClient *openai.Client, root *cobra.Command) *BatchComman
 // create is the handler for the "batch create" command.
 func (c *BatchCommand) create(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	window, _ := cmd.Flags().GetString("completion-window")
+	if !slices.Contains(openai.BatchEndpoints, endpoint) {
+		return fmt.Errorf("invalid endpoint %s: expecting %s", endpoint, strings.Join(openai.BatchEndpoints, ", "))
+	}
+	if !slices.Contains(openai.BatchCompletionWindows, window) {
+		return fmt.Errorf("invalid completion window %s: expecting %s", window, strings.Join(openai.BatchCompletionWindows, ", "))
+	}
 
 	// Validate the input file
 	inputFileID := args[0]
@@ -111,8 +208,8 @@ func (c *BatchCommand) create(cmd *cobra.Command, args []string) error {
 	// Create the batch operation
 	request := openai.BatchRequest{
 		InputFileID:      inputFileID,
-		Endpoint:         "/v1/chat/completions",
-		CompletionWindow: "24h",
+		Endpoint:         endpoint,
+		CompletionWindow: window,
 		Metadata: map[string]string{
 			"input_file": f.FileName,
 		},
@@ -139,6 +236,7 @@ func (c *BatchCommand) create(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	registerJobs(psy.NewBatchJob(b, nil))
 	j, err := json.MarshalIndent(b, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error marshalling Batch JSON: %w", err)
@@ -147,6 +245,88 @@ func (c *BatchCommand) create(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// submit is the handler for the "batch submit" command.
+func (c *BatchCommand) submit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	metadata, _ := cmd.Flags().GetStringToString("metadata")
+	window, _ := cmd.Flags().GetString("completion-window")
+	validateOnly, _ := cmd.Flags().GetBool("validate")
+	wait, _ := cmd.Flags().GetInt("wait")
+	path := args[0]
+	if !slices.Contains(openai.BatchCompletionWindows, window) {
+		return fmt.Errorf("invalid completion window %s: expecting %s", window, strings.Join(openai.BatchCompletionWindows, ", "))
+	}
+
+	// Validate the input file:
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read batch input file %s: %w", path, err)
+	}
+	input := openai.ValidateBatchInput(data)
+	for _, e := range input.Errors {
+		fmt.Fprintln(os.Stderr, e.Error())
+	}
+	if !input.IsValid() {
+		return fmt.Errorf("invalid batch input file %s: %d problems found", path, len(input.Errors))
+	}
+	fmt.Printf("validated %s: %d %s requests for model %s (%d bytes)\n", path, input.Requests,
+		input.Endpoint, cmp.Or(input.Model, "default"), input.Bytes)
+	if validateOnly {
+		return nil
+	}
+
+	// Upload the input file and create the batch operation:
+	file, err := c.apiClient.UploadFile(ctx, filepath.Base(path), "batch", data)
+	if err != nil {
+		return fmt.Errorf("upload batch input file %s: %w", path, err)
+	}
+	fmt.Printf("uploaded %s input file %s: %s\n", file.Purpose, file.ID, file.FileName)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	metadata["input_file"] = cmp.Or(metadata["input_file"], path)
+	if input.Model != "" {
+		metadata["model"] = cmp.Or(metadata["model"], input.Model)
+	}
+	b, err := c.apiClient.CreateBatch(ctx, openai.BatchRequest{
+		InputFileID:      file.ID,
+		Endpoint:         input.Endpoint,
+		CompletionWindow: window,
+		Metadata:         psy.LimitMetadata(metadata),
+	})
+	if err != nil {
+		return fmt.Errorf("create batch: %w", err)
+	}
+	fmt.Printf("created batch %s: %s\n", b.ID, b.Status)
+	job := psy.NewBatchJob(b, nil)
+	job.Model = input.Model
+	job.Files = map[string]string{psy.AbsPath(path): psy.HashData(data)}
+	registerJobs(job)
+
+	// Poll the batch operation for completion:
+	if wait <= 0 {
+		fmt.Println("Use the following command to monitor progress:")
+		fmt.Printf("gpt batch monitor %s\n", b.ID)
+		fmt.Println("Once the batch is done, use the following command to export the results:")
+		fmt.Printf("gpt batch export %s\n", b.ID)
+		return nil
+	}
+	fmt.Println("polling for batch completion... (Ctrl+C to cancel)")
+	for !b.IsDone() {
+		if err = sleep(ctx, time.Duration(wait)*time.Second); err != nil {
+			return err
+		}
+		if b, err = c.apiClient.ReadBatch(ctx, b.ID); err != nil {
+			return err
+		}
+		fmt.Println(b.Progress())
+	}
+	registerJobs(psy.NewBatchJob(b, nil))
+	fmt.Println("Use the following command to export the results:")
+	fmt.Printf("gpt batch export %s\n", b.ID)
+	return nil
+}
+
 // read is the handler for the "batch read" command.
 func (c *BatchCommand) read(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
@@ -180,21 +360,26 @@ func (c *BatchCommand) read(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// monitor is the handler for the "batch monitor" command.
+// monitor is the handler for the "batch monitor" command. If the batch is part
+// of a run that was split into multiple batches, the progress of the run is reported.
 func (c *BatchCommand) monitor(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	wait, _ := cmd.Flags().GetInt("wait")
-	batchID := args[0]
+	run, err := psy.ReadRun(ctx, c.apiClient, args[0])
+	if err != nil {
+		return err
+	}
 	for {
-		batch, err := c.apiClient.ReadBatch(ctx, batchID)
-		if err != nil {
-			return fmt.Errorf("read batch %s: %w", batchID, err)
-		}
-		fmt.Println(batch.Progress())
-		if batch.IsDone() {
+		fmt.Println(run.Progress())
+		if run.IsDone() {
 			break
 		}
-		time.Sleep(time.Duration(wait) * time.Second)
+		if err = sleep(ctx, time.Duration(wait)*time.Second); err != nil {
+			return err
+		}
+		if err = run.Refresh(ctx, c.apiClient); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -226,6 +411,334 @@ func (c *BatchCommand) cancel(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// retry is the handler for the "batch retry" command.
+func (c *BatchCommand) retry(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Identify the failed or missing requests:
+	run, err := psy.ReadRun(ctx, c.apiClient, args[0])
+	if err != nil {
+		return err
+	}
+	if !run.IsDone() {
+		return fmt.Errorf("run %s is not done: %s", run.ID, run.Progress())
+	}
+	responses, _, err := run.ReadResponses(ctx, c.apiClient)
+	if err != nil {
+		return err
+	}
+	lines, err := run.FailedRequests(ctx, c.apiClient, responses)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		fmt.Printf("No failed or missing requests found in run %s.\n", run.ID)
+		return nil
+	}
+	fmt.Printf("retrying %d failed or missing requests from run %s\n", len(lines), run.ID)
+
+	// Submit the follow-up batch(es), with the original run metadata (and parameters, if registered):
+	registry, err := openRegistry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning:", err)
+		registry = &psy.Registry{}
+	}
+	original := run.Batches[0]
+	inputPath := strings.TrimSuffix(cmp.Or(original.Metadata["input_file"], run.ID+".jsonl"), ".jsonl")
+	if original.Metadata["run_parts"] != "" {
+		inputPath = strings.TrimSuffix(inputPath, "-1")
+	}
+	// If any part can't be submitted, the retry batches already created are cancelled,
+	// so that the run can be retried again without duplicating requests:
+	parts := psy.SplitBatchInput(lines, openai.MaxBatchRequests, openai.MaxBatchBytes)
+	retries := psy.Run{ID: run.ID}
+	for i, part := range parts {
+		partPath := inputPath + "-retry.jsonl"
+		if len(parts) > 1 {
+			partPath = fmt.Sprintf("%s-retry-%d.jsonl", inputPath, i+1)
+		}
+		file, e := c.apiClient.UploadFile(ctx, partPath, "batch", part)
+		if e != nil {
+			abandonRun(ctx, c.apiClient, retries)
+			return fmt.Errorf("upload batch input file %s: %w", partPath, e)
+		}
+		fmt.Printf("uploaded %s input file %s: %s\n", file.Purpose, file.ID, file.FileName)
+		metadata := maps.Clone(original.Metadata)
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		delete(metadata, "run_part")
+		metadata["run_id"] = run.ID
+		metadata["retry_of"] = run.ID
+		metadata["input_file"] = partPath
+		b, e := c.apiClient.CreateBatch(ctx, openai.BatchRequest{
+			InputFileID:      file.ID,
+			Endpoint:         original.Endpoint,
+			CompletionWindow: original.CompletionWindow,
+			Metadata:         psy.LimitMetadata(metadata),
+		})
+		if e != nil {
+			abandonRun(ctx, c.apiClient, retries)
+			return fmt.Errorf("create batch: %w", e)
+		}
+		fmt.Printf("created batch %s: %s\n", b.ID, b.Status)
+		retries.Batches = append(retries.Batches, b)
+		job := psy.NewBatchJob(b, nil)
+		if p, ok := registry.Parameters(run.ID); ok {
+			p.InputFile = partPath
+			job = psy.NewBatchJob(b, &p)
+		}
+		registerJobs(job)
+	}
+	fmt.Println("Use the following command to monitor progress:")
+	fmt.Printf("gpt batch monitor %s\n", run.ID)
+	fmt.Println("Once the batch is done, use the following command to process the results:")
+	fmt.Printf("gpt chat results %s\n", run.ID)
+	return nil
+}
+
+// export is the handler for the "batch export" command.
+func (c *BatchCommand) export(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	outputPath, _ := cmd.Flags().GetString("output")
+	joinPath, _ := cmd.Flags().GetString("join")
+	on, _ := cmd.Flags().GetString("on")
+	scoreField, _ := cmd.Flags().GetString("score-field")
+	scoreSelect, _ := cmd.Flags().GetString("score-select")
+
+	// Read the results of the run:
+	run, err := psy.ReadRun(ctx, c.apiClient, args[0])
+	if err != nil {
+		return err
+	}
+	if !run.IsDone() {
+		fmt.Fprintln(os.Stderr, "warning: exporting partial results:", run.Progress())
+	}
+	responses, skipped, err := run.ReadResponses(ctx, c.apiClient)
+	if err != nil {
+		return err
+	}
+	for _, b := range skipped {
+		fmt.Printf("warning: batch %s status %s has no results\n", b.ID, b.Status)
+	}
+	if outputPath == "" {
+		outputPath = run.ID + ".csv"
+	}
+
+	// Flatten the responses, or join them to the answers table:
+	table := psy.ResponseTable(responses)
+	if joinPath != "" {
+		table, err = psy.ReadCSVTable(joinPath)
+		if err != nil {
+			return err
+		}
+		if !table.HasField(on) {
+			return fmt.Errorf("join field %s not found in %s", on, joinPath)
+		}
+		metadata := run.Metadata()
+		scoreField = cmp.Or(scoreField, metadata["score_field"], "score")
+		sel := psy.Selection(strings.ToLower(cmp.Or(scoreSelect, metadata["score_select"], "last")))
+		if !sel.IsValid() {
+			return fmt.Errorf("invalid score selection %s: expecting first, last, all, or none", sel)
+		}
+		if missing := psy.MergeResponses(table, on, responses, scoreField, sel); missing > 0 {
+			fmt.Printf("warning: %d records of %s have no results\n", missing, joinPath)
+		}
+	}
+
+	// Write the output file:
+	if strings.HasSuffix(strings.ToLower(outputPath), ".jsonl") {
+		err = table.WriteJSONL(outputPath)
+	} else {
+		err = table.WriteCSV(outputPath)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("exported %d results of run %s to %s\n", len(responses), run.ID, outputPath)
+	return nil
+}
+
+// maxResultAttempts is the number of polls on which batch watch tries to process
+// the results of a finished run, before giving up on it.
+const maxResultAttempts = 5
+
+// watchHorizon limits the search for batches in progress to those created recently,
+// allowing for the longest completion window and time to finalize the results.
+const watchHorizon = 72 * time.Hour
+
+// watch is the handler for the "batch watch" command.
+func (c *BatchCommand) watch(cmd *cobra.Command, args []string) error {
+	wait, _ := cmd.Flags().GetInt("wait")
+	maxWait, _ := cmd.Flags().GetInt("max-wait")
+	jobs, _ := cmd.Flags().GetBool("jobs")
+	keep, _ := cmd.Flags().GetBool("keep")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	status := make(map[string]string)  // last known status of each watched batch
+	pending := make(map[string]string) // runs awaiting results processing: run ID -> member batch ID
+	processed := make(map[string]bool) // runs with processed results
+	attempts := make(map[string]int)   // failed attempts to process each pending run's results
+	var failed []string                // runs whose results couldn't be processed
+	interval := time.Duration(max(wait, 1)) * time.Second
+	backoff := func() time.Duration {
+		return min(2*interval, time.Duration(max(maxWait, wait, 1))*time.Second)
+	}
+	fmt.Println("watching batch operations... (Ctrl+C to stop)")
+	for {
+		// Identify the batches in progress, and any that have finished since the last poll.
+		// If they can't be read, the batches are still in progress, so back off and retry:
+		batches, err := c.watchedBatches(ctx, jobs, status)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, "warning:", err)
+			interval = backoff()
+			if err = sleep(ctx, interval); err != nil {
+				return nil
+			}
+			continue
+		}
+		changed := false
+		inProgress := 0
+		for _, b := range batches {
+			if prior, ok := status[b.ID]; !ok || prior != b.Status {
+				fmt.Printf("%s\t%s\t%s -> %s\n", time.Now().Format(time.DateTime), b.ID, cmp.Or(prior, "new"), b.Status)
+				status[b.ID] = b.Status
+				changed = true
+				if b.IsDone() && ok && !processed[psy.RunID(b)] {
+					pending[psy.RunID(b)] = b.ID
+				}
+			}
+			if !b.IsDone() {
+				inProgress++
+			}
+		}
+		if jobs && changed {
+			updates := make([]psy.Job, len(batches))
+			for i, b := range batches {
+				updates[i] = psy.NewBatchJob(b, nil)
+			}
+			registerJobs(updates...)
+		}
+
+		// Process the results of runs whose batches are all done, retrying failures
+		// on the following polls, up to a limit:
+		for runID, batchID := range pending {
+			run, e := psy.ReadRun(ctx, c.apiClient, batchID)
+			if e == nil && !run.IsDone() {
+				fmt.Println(run.Progress())
+				delete(pending, runID)
+				continue
+			}
+			if e == nil {
+				e = processRunResults(ctx, c.apiClient, run)
+			}
+			if e != nil {
+				attempts[runID]++
+				fmt.Fprintf(os.Stderr, "warning: run %s results not processed (attempt %d of %d): %v\n",
+					runID, attempts[runID], maxResultAttempts, e)
+				if attempts[runID] >= maxResultAttempts {
+					failed = append(failed, runID)
+					delete(pending, runID)
+				}
+				continue
+			}
+			processed[runID] = true
+			delete(pending, runID)
+		}
+
+		// Stop, or wait (backing off while nothing changes, or while results are retried):
+		if inProgress == 0 && len(pending) == 0 && !keep {
+			if len(failed) > 0 {
+				return fmt.Errorf("results of %d finished runs not processed (see \"gpt chat results\"): %s",
+					len(failed), strings.Join(failed, ", "))
+			}
+			fmt.Println("no batch operations in progress")
+			return nil
+		}
+		if changed {
+			interval = time.Duration(max(wait, 1)) * time.Second
+		} else {
+			interval = backoff()
+		}
+		if err = sleep(ctx, interval); err != nil {
+			return nil
+		}
+	}
+}
+
+// watchedBatches reads the batches in progress, from the batch list (or the local
+// job registry), along with the watched batches that have finished since they
+// were last seen.
+func (c *BatchCommand) watchedBatches(ctx context.Context, jobs bool, status map[string]string) ([]openai.Batch, error) {
+	var batches []openai.Batch
+	found := make(map[string]bool)
+	if jobs {
+		r, err := openRegistry()
+		if err != nil {
+			return batches, err
+		}
+		for _, job := range r.List(psy.JobFilter{Kind: psy.JobBatch}) {
+			if !job.IsDone() {
+				status[job.ID] = cmp.Or(status[job.ID], job.Status)
+			}
+		}
+	} else {
+		after := ""
+		horizon := time.Now().Add(-watchHorizon).Unix()
+		for {
+			list, hasMore, lastID, err := c.apiClient.ListBatches(ctx, 100, after)
+			if err != nil {
+				return batches, err
+			}
+			for _, b := range list {
+				if b.CreatedAt >= horizon && !b.IsDone() {
+					batches = append(batches, b)
+					found[b.ID] = true
+				}
+			}
+			if !hasMore || lastID == "" || len(list) == 0 || list[len(list)-1].CreatedAt < horizon {
+				break
+			}
+			after = lastID
+		}
+	}
+
+	// Re-read the watched batches that are no longer listed as in progress:
+	for id, s := range status {
+		if found[id] || (s != "" && isDoneStatus(s)) {
+			continue
+		}
+		b, err := c.apiClient.ReadBatch(ctx, id)
+		if err != nil {
+			return batches, err
+		}
+		batches = append(batches, b)
+	}
+	return batches, nil
+}
+
+// isDoneStatus returns true if a batch status is terminal.
+func isDoneStatus(status string) bool {
+	b := openai.Batch{Status: status}
+	return b.IsDone()
+}
+
+// sleep pauses for the specified duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 // list is the handler for the "batch list" command.
 func (c *BatchCommand) list(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
@@ -1,13 +1,15 @@
 package cli
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"gpt/openai"
 	"gpt/psy"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,11 +27,16 @@ type ChatCommand struct {
 	parallelCmd   *cobra.Command
 	batchCmd      *cobra.Command
 	resultsCmd    *cobra.Command
+	storedCmd     *StoredCommand
 	raw           bool
 	verbose       bool
 	model         string
 	temperature   float32
 	maxTokens     int
+	effort        string
+	verbosity     string
+	store         bool
+	conversation  string
 	questionField string
 	questionID    string
 	answerField   string
@@ -53,6 +60,9 @@ func NewChatCommand(apiClient *openai.Client, root *cobra.Command) *ChatCommand
 	c.baseCmd.PersistentFlags().StringVarP(&c.model, "model", "m", "gpt-5", "Model ID")
 	c.baseCmd.PersistentFlags().Float32VarP(&c.temperature, "temperature", "T", 1.0, "Temperature for sampling")
 	c.baseCmd.PersistentFlags().IntVarP(&c.maxTokens, "max-tokens", "t", 0, "Maximum number of tokens to generate")
+	c.baseCmd.PersistentFlags().StringVarP(&c.effort, "reasoning-effort", "e", "", "Reasoning effort: minimal | low | medium | high (reasoning models only)")
+	c.baseCmd.PersistentFlags().StringVarP(&c.verbosity, "verbosity", "V", "", "Response verbosity: low | medium | high (gpt-5 models only)")
+	c.baseCmd.PersistentFlags().BoolVar(&c.store, "store", false, "Store completions, tagged with metadata, for later review?")
 	c.rootCmd.AddCommand(c.baseCmd)
 
 	// Prompt Command
@@ -60,10 +70,12 @@ func NewChatCommand(apiClient *openai.Client, root *cobra.Command) *ChatCommand
 	c.promptCmd = &cobra.Command{
 		Use:   "prompt <promptFile> [systemFile]",
 		Short: "Chat complete a test prompt",
-		Long:  "Chat complete a test prompt from a specified file.",
-		Args:  cobra.MinimumNArgs(1),
-		RunE:  c.prompt,
+		Long: "Chat complete a test prompt from a specified file. With a conversation template (YAML or\n" +
+			"JSON, specified with --conversation), the prompt and system files are omitted.",
+		Args: c.templateArgs(1),
+		RunE: c.prompt,
 	}
+	c.promptCmd.Flags().StringVarP(&c.conversation, "conversation", "c", "", "Conversation template file, instead of prompt and system files")
 	c.promptCmd.Flags().BoolVarP(&c.raw, "raw", "r", false, "Raw OpenAI Response?")
 	c.promptCmd.Flags().BoolVarP(&c.verbose, "verbose", "v", false, "Verbose output?")
 	c.promptCmd.Flags().StringVarP(&c.scoreSelect, "score-select", "S", "none", "Score selection: first | last | all | none")
@@ -74,10 +86,12 @@ func NewChatCommand(apiClient *openai.Client, root *cobra.Command) *ChatCommand
 	c.randomCmd = &cobra.Command{
 		Use:   "random <promptFile> <systemFile> <answerFile> [questionFile]",
 		Short: "Chat complete a random answer",
-		Long:  "Chat complete a random answer from a specified file.",
-		Args:  cobra.MinimumNArgs(3),
-		RunE:  c.random,
+		Long: "Chat complete a random answer from a specified file. With a conversation template\n" +
+			"(--conversation), the prompt and system files are omitted.",
+		Args: c.templateArgs(3),
+		RunE: c.random,
 	}
+	c.randomCmd.Flags().StringVarP(&c.conversation, "conversation", "c", "", "Conversation template file, instead of prompt and system files")
 	c.randomCmd.Flags().BoolVarP(&c.raw, "raw", "r", false, "Raw OpenAI Response?")
 	c.randomCmd.Flags().BoolVarP(&c.verbose, "verbose", "v", false, "Verbose output?")
 	c.randomCmd.Flags().StringVarP(&c.scoreSelect, "score-select", "S", "last", "Score selection: first | last | all | none")
@@ -93,10 +107,12 @@ func NewChatCommand(apiClient *openai.Client, root *cobra.Command) *ChatCommand
 	c.parallelCmd = &cobra.Command{
 		Use:   "parallel <outputFile> <promptFile> <systemFile> <answerFile> [questionFile]",
 		Short: "Chat complete answers in parallel",
-		Long:  "Chat complete answers from a specified file with concurrent requests.",
-		Args:  cobra.MinimumNArgs(4),
-		RunE:  c.parallel,
+		Long: "Chat complete answers from a specified file with concurrent requests. With a conversation\n" +
+			"template (--conversation), the prompt and system files are omitted.",
+		Args: c.templateArgs(4),
+		RunE: c.parallel,
 	}
+	c.parallelCmd.Flags().StringVarP(&c.conversation, "conversation", "c", "", "Conversation template file, instead of prompt and system files")
 	c.parallelCmd.Flags().IntP("batch-size", "b", 20, "Concurrent request batch size")
 	c.parallelCmd.Flags().StringVarP(&c.scoreField, "score-field", "s", "score", "Score field name")
 	c.parallelCmd.Flags().StringVarP(&c.scoreSelect, "score-select", "S", "last", "Score selection: first | last | all | none")
@@ -111,10 +127,12 @@ func NewChatCommand(apiClient *openai.Client, root *cobra.Command) *ChatCommand
 	c.batchCmd = &cobra.Command{
 		Use:   "batch <outputFile> <promptFile> <systemFile> <answerFile> [questionFile]",
 		Short: "Chat complete answers as an asynchronous batch",
-		Long:  "Chat complete answers from a specified file as an asynchronous batch.",
-		Args:  cobra.MinimumNArgs(4),
-		RunE:  c.batchCreate,
+		Long: "Chat complete answers from a specified file as an asynchronous batch. With a conversation\n" +
+			"template (--conversation), the prompt and system files are omitted.",
+		Args: c.templateArgs(4),
+		RunE: c.batchCreate,
 	}
+	c.batchCmd.Flags().StringVarP(&c.conversation, "conversation", "c", "", "Conversation template file, instead of prompt and system files")
 	c.batchCmd.Flags().IntP("wait", "w", 0, "Wait for results? Polling interval in seconds (recommend 10)")
 	c.batchCmd.Flags().BoolP("input-only", "i", false, "Generate JSONL input file only?")
 	c.batchCmd.Flags().StringVarP(&c.scoreField, "score-field", "s", "score", "Score field name")
@@ -128,85 +146,109 @@ func NewChatCommand(apiClient *openai.Client, root *cobra.Command) *ChatCommand
 	// Results Command
 	// Example: gpt chat results <batchID>
 	c.resultsCmd = &cobra.Command{
-		Use:   "results <batchID>",
+		Use:   "results <batchID|runID>",
 		Short: "Process batch results",
-		Long:  "Process the results of a completed batch operation.",
-		Args:  cobra.ExactArgs(1),
-		RunE:  c.batchResults,
+		Long: "Process the results of a completed batch operation. If the batch is part of a run that\n" +
+			"was split into multiple batches, the results of all the batches are merged.",
+		Args: cobra.ExactArgs(1),
+		RunE: c.batchResults,
 	}
 	c.baseCmd.AddCommand(c.resultsCmd)
 
+	// Stored Completions Commands
+	c.storedCmd = NewStoredCommand(apiClient, c.baseCmd)
+
 	return c
 }
 
 // prompt chat-completes a specified prompt.
 func (c *ChatCommand) prompt(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	promptPath := args[0]
-	systemPath := ""
-	if len(args) > 1 {
-		systemPath = args[1]
-	}
-
-	// Validate the score selection:
-	sel := psy.Selection(strings.ToLower(c.scoreSelect))
-	if !sel.IsValid() {
-		return fmt.Errorf("invalid score selection (expect first, last, all, or none): %s", c.scoreSelect)
-	}
+	promptPath, systemPath, _ := c.templatePaths(args, 0)
 
-	// Validate the model:
-	if !c.apiClient.ValidModel(ctx, c.model) {
-		return fmt.Errorf("model %s is not a recognized model ID", c.model)
+	// Identify and validate Chat Parameters:
+	p := psy.ChatParameters{
+		SystemFile:       systemPath,
+		PromptFile:       promptPath,
+		ConversationFile: c.conversation,
+		ScoreSelect:      psy.Selection(strings.ToLower(c.scoreSelect)),
+		Model:            c.model,
+		Temperature:      c.temperature,
+		MaxTokens:        c.maxTokens,
+		ReasoningEffort:  openai.ReasoningEffort(strings.ToLower(c.effort)),
+		Verbosity:        openai.Verbosity(strings.ToLower(c.verbosity)),
+		Store:            c.store,
+	}
+	if err := c.validateParameters(ctx, p, openai.EndpointChat); err != nil {
+		return err
 	}
 
-	// Read the system and prompt files:
-	system, err := psy.ReadTextFile(systemPath)
-	if err != nil {
-		return fmt.Errorf("system file: %w", err)
-	}
-	prompt, err := psy.ReadTextFile(promptPath)
-	if err != nil {
-		return fmt.Errorf("prompt file: %w", err)
+	// Read the system and prompt files, or render the conversation template:
+	var messages []openai.Message
+	if c.conversation != "" {
+		conversation, err := psy.ReadConversation(c.conversation)
+		if err != nil {
+			return err
+		}
+		if conversation.Examples != nil {
+			return fmt.Errorf("conversation %s: few-shot examples require an answer file", c.conversation)
+		}
+		if messages, err = conversation.Render(psy.Record{}, nil); err != nil {
+			return fmt.Errorf("conversation %s: %w", c.conversation, err)
+		}
+	} else {
+		system, err := psy.ReadTextFile(systemPath)
+		if err != nil {
+			return fmt.Errorf("system file: %w", err)
+		}
+		prompt, err := psy.ReadTextFile(promptPath)
+		if err != nil {
+			return fmt.Errorf("prompt file: %w", err)
+		}
+		messages = psy.ChatMessages(system, prompt)
 	}
 
 	// Generate and output a chat response:
 	chatID := tuid.NewID().String()
-	chat := psy.NewChat(chatID, system, prompt, c.model, c.temperature, c.maxTokens)
-	return c.generateChatResponse(ctx, chat, sel)
+	chat := psy.NewChatMessages(chatID, messages, p)
+	return c.generateChatResponse(ctx, chat, p.ScoreSelect)
 }
 
 // random chat-completes a random prompt from the specified answer file.
 func (c *ChatCommand) random(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	promptPath := args[0]
-	systemPath := args[1]
-	answerPath := args[2]
+	promptPath, systemPath, args := c.templatePaths(args, 0)
+	answerPath := args[0]
 	questionPath := ""
-	if len(args) > 3 {
-		questionPath = args[3]
+	if len(args) > 1 {
+		questionPath = args[1]
 	}
 
 	// Identify Chat Parameters:
 	p := psy.ChatParameters{
-		InputFile:     "",
-		OutputFile:    "",
-		SystemFile:    systemPath,
-		PromptFile:    promptPath,
-		QuestionFile:  questionPath,
-		QuestionField: c.questionField,
-		QuestionID:    c.questionID,
-		AnswerFile:    answerPath,
-		AnswerField:   c.answerField,
-		AnswerID:      c.answerID,
-		ScoreField:    c.scoreField,
-		ScoreSelect:   psy.Selection(strings.ToLower(c.scoreSelect)),
-		Model:         c.model,
-		Temperature:   c.temperature,
-		MaxTokens:     c.maxTokens,
+		InputFile:        "",
+		OutputFile:       "",
+		SystemFile:       systemPath,
+		PromptFile:       promptPath,
+		ConversationFile: c.conversation,
+		QuestionFile:     questionPath,
+		QuestionField:    c.questionField,
+		QuestionID:       c.questionID,
+		AnswerFile:       answerPath,
+		AnswerField:      c.answerField,
+		AnswerID:         c.answerID,
+		ScoreField:       c.scoreField,
+		ScoreSelect:      psy.Selection(strings.ToLower(c.scoreSelect)),
+		Model:            c.model,
+		Temperature:      c.temperature,
+		MaxTokens:        c.maxTokens,
+		ReasoningEffort:  openai.ReasoningEffort(strings.ToLower(c.effort)),
+		Verbosity:        openai.Verbosity(strings.ToLower(c.verbosity)),
+		Store:            c.store,
 	}
 
 	// Generate the chat request:
-	chats, _, err := c.generateChatRequests(p)
+	chats, _, err := c.generateChatRequests(p, openai.EndpointChat)
 	if err != nil {
 		return fmt.Errorf("generate chat request: %w", err)
 	}
@@ -229,36 +271,39 @@ func (c *ChatCommand) parallel(cmd *cobra.Command, args []string) error {
 	startTime := time.Now()
 	ctx := context.Background()
 	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	promptPath, systemPath, args := c.templatePaths(args, 1)
 	outputPath := args[0]
-	promptPath := args[1]
-	systemPath := args[2]
-	answerPath := args[3]
+	answerPath := args[1]
 	questionPath := ""
-	if len(args) > 4 {
-		questionPath = args[4]
+	if len(args) > 2 {
+		questionPath = args[2]
 	}
 
 	// Identify Chat Parameters:
 	p := psy.ChatParameters{
-		InputFile:     "",
-		OutputFile:    outputPath,
-		SystemFile:    systemPath,
-		PromptFile:    promptPath,
-		QuestionFile:  questionPath,
-		QuestionField: c.questionField,
-		QuestionID:    c.questionID,
-		AnswerFile:    answerPath,
-		AnswerField:   c.answerField,
-		AnswerID:      "",
-		ScoreField:    c.scoreField,
-		ScoreSelect:   psy.Selection(strings.ToLower(c.scoreSelect)),
-		Model:         c.model,
-		Temperature:   c.temperature,
-		MaxTokens:     c.maxTokens,
+		InputFile:        "",
+		OutputFile:       outputPath,
+		SystemFile:       systemPath,
+		PromptFile:       promptPath,
+		ConversationFile: c.conversation,
+		QuestionFile:     questionPath,
+		QuestionField:    c.questionField,
+		QuestionID:       c.questionID,
+		AnswerFile:       answerPath,
+		AnswerField:      c.answerField,
+		AnswerID:         "",
+		ScoreField:       c.scoreField,
+		ScoreSelect:      psy.Selection(strings.ToLower(c.scoreSelect)),
+		Model:            c.model,
+		Temperature:      c.temperature,
+		MaxTokens:        c.maxTokens,
+		ReasoningEffort:  openai.ReasoningEffort(strings.ToLower(c.effort)),
+		Verbosity:        openai.Verbosity(strings.ToLower(c.verbosity)),
+		Store:            c.store,
 	}
 
 	// Generate the chat requests:
-	chats, answers, err := c.generateChatRequests(p)
+	chats, answers, err := c.generateChatRequests(p, openai.EndpointChat)
 	if err != nil {
 		return fmt.Errorf("generate chat requests: %w", err)
 	}
@@ -379,114 +424,151 @@ func (c *ChatCommand) batchCreate(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	wait, _ := cmd.Flags().GetInt("wait")
 	inputOnly, _ := cmd.Flags().GetBool("input-only")
+	promptPath, systemPath, args := c.templatePaths(args, 1)
 	outputPath := args[0]
-	promptPath := args[1]
-	systemPath := args[2]
-	answerPath := args[3]
+	answerPath := args[1]
 	questionPath := ""
-	if len(args) > 4 {
-		questionPath = args[4]
+	if len(args) > 2 {
+		questionPath = args[2]
 	}
 	inputPath := strings.TrimSuffix(answerPath, ".csv") + ".jsonl"
 
 	// Identify Chat Parameters:
 	p := psy.ChatParameters{
-		InputFile:     inputPath,
-		OutputFile:    outputPath,
-		SystemFile:    systemPath,
-		PromptFile:    promptPath,
-		QuestionFile:  questionPath,
-		QuestionField: c.questionField,
-		QuestionID:    c.questionID,
-		AnswerFile:    answerPath,
-		AnswerField:   c.answerField,
-		AnswerID:      "",
-		ScoreField:    c.scoreField,
-		ScoreSelect:   psy.Selection(strings.ToLower(c.scoreSelect)),
-		Model:         c.model,
-		Temperature:   c.temperature,
-		MaxTokens:     c.maxTokens,
+		InputFile:        inputPath,
+		OutputFile:       outputPath,
+		SystemFile:       systemPath,
+		PromptFile:       promptPath,
+		ConversationFile: c.conversation,
+		QuestionFile:     questionPath,
+		QuestionField:    c.questionField,
+		QuestionID:       c.questionID,
+		AnswerFile:       answerPath,
+		AnswerField:      c.answerField,
+		AnswerID:         "",
+		ScoreField:       c.scoreField,
+		ScoreSelect:      psy.Selection(strings.ToLower(c.scoreSelect)),
+		Model:            c.model,
+		Temperature:      c.temperature,
+		MaxTokens:        c.maxTokens,
+		ReasoningEffort:  openai.ReasoningEffort(strings.ToLower(c.effort)),
+		Verbosity:        openai.Verbosity(strings.ToLower(c.verbosity)),
+		Store:            c.store,
 	}
 
 	// Generate the chat requests:
-	chats, answers, err := c.generateChatRequests(p)
+	chats, answers, err := c.generateChatRequests(p, openai.EndpointChat, openai.EndpointBatch)
 	if err != nil {
 		return fmt.Errorf("generate chat requests: %w", err)
 	}
 
-	// Generate and upload the batch input file:
-	var inputData bytes.Buffer
+	// Generate the batch input, split into parts within the batch input limits:
+	var lines [][]byte
 	for _, chat := range chats {
-		item := openai.BatchRequestItem{
-			CustomID: chat.ID,
-			Method:   "POST",
-			URL:      "/v1/chat/completions",
-			Body:     chat.Request,
+		item, e := openai.NewBatchRequestItem(chat.ID, openai.EndpointChat, chat.Request)
+		if e != nil {
+			return e
 		}
 		b, e := json.Marshal(item)
 		if e != nil {
 			return fmt.Errorf("marshal chat batch request item: %w", e)
 		}
-		inputData.Write(b)
-		inputData.WriteString("\n")
+		lines = append(lines, b)
+	}
+	parts := psy.SplitBatchInput(lines, openai.MaxBatchRequests, openai.MaxBatchBytes)
+	partPath := func(i int) string {
+		if len(parts) == 1 {
+			return inputPath
+		}
+		return fmt.Sprintf("%s-%d.jsonl", strings.TrimSuffix(inputPath, ".jsonl"), i+1)
 	}
-	inputBytes := inputData.Bytes()
 	if inputOnly {
-		if e := os.WriteFile(inputPath, inputBytes, 0644); e != nil {
-			return fmt.Errorf("save batch input file %s: %w", inputPath, e)
+		for i, part := range parts {
+			if e := os.WriteFile(partPath(i), part, 0644); e != nil {
+				return fmt.Errorf("save batch input file %s: %w", partPath(i), e)
+			}
+			fmt.Printf("saved batch input file %s\n", partPath(i))
 		}
-		fmt.Printf("saved batch input file %s\n", inputPath)
 		return nil
 	}
-	file, err := c.apiClient.UploadFile(ctx, inputPath, "batch", inputBytes)
-	if err != nil {
-		return fmt.Errorf("upload batch input file %s: %w", inputPath, err)
-	}
-	fmt.Printf("uploaded %s input file %s: %s\n", file.Purpose, file.ID, file.FileName)
-
-	// Create the batch operation:
-	batchRequest := openai.BatchRequest{
-		InputFileID:      file.ID,
-		Endpoint:         "/v1/chat/completions",
-		CompletionWindow: "24h",
-		Metadata:         p.Metadata(),
-	}
-	batch, err := c.apiClient.CreateBatch(ctx, batchRequest)
-	if err != nil {
-		return fmt.Errorf("create batch: %w", err)
-	}
-	fmt.Printf("created batch %s: %s\n", batch.ID, batch.Status)
 
-	// Save the answers table as an incomplete results CSV file with Chat IDs:
+	// Save the answers table as an incomplete results CSV file with Chat IDs, before
+	// submitting any batches, so that their results can always be joined to the answers:
 	err = answers.WriteCSV(outputPath)
 	if err != nil {
 		return fmt.Errorf("save incomplete results file %s: %w", outputPath, err)
 	}
 	fmt.Printf("saved incomplete results file (with chat IDs): %s\n", outputPath)
 
-	// Poll the batch operation for completion:
+	// Upload the input file(s) and create the batch operation(s), tied together by a run ID:
+	run := psy.Run{ID: tuid.NewID().String()}
+	if len(parts) > 1 {
+		fmt.Printf("splitting %d requests into %d batches for run %s\n", len(lines), len(parts), run.ID)
+	}
+	for i, part := range parts {
+		file, e := c.apiClient.UploadFile(ctx, partPath(i), "batch", part)
+		if e != nil {
+			abandonRun(ctx, c.apiClient, run)
+			return fmt.Errorf("upload batch input file %s: %w", partPath(i), e)
+		}
+		fmt.Printf("uploaded %s input file %s: %s\n", file.Purpose, file.ID, file.FileName)
+		metadata := p.Metadata()
+		metadata["input_file"] = partPath(i)
+		if len(parts) > 1 {
+			metadata["run_id"] = run.ID
+			metadata["run_part"] = strconv.Itoa(i + 1)
+			metadata["run_parts"] = strconv.Itoa(len(parts))
+		}
+		batchRequest := openai.BatchRequest{
+			InputFileID:      file.ID,
+			Endpoint:         openai.EndpointChat,
+			CompletionWindow: "24h",
+			Metadata:         psy.LimitMetadata(metadata),
+		}
+		batch, e := c.apiClient.CreateBatch(ctx, batchRequest)
+		if e != nil {
+			abandonRun(ctx, c.apiClient, run)
+			return fmt.Errorf("create batch: %w", e)
+		}
+		fmt.Printf("created batch %s: %s\n", batch.ID, batch.Status)
+		run.Batches = append(run.Batches, batch)
+		params := p
+		params.InputFile = partPath(i)
+		job := psy.NewBatchJob(batch, &params)
+		if job.Files == nil {
+			job.Files = make(map[string]string)
+		}
+		job.Files[psy.AbsPath(partPath(i))] = psy.HashData(part)
+		registerJobs(job)
+	}
+	if len(run.Batches) == 1 {
+		run.ID = run.Batches[0].ID
+	}
+
+	// Poll the batch operation(s) for completion:
 	if wait > 0 {
 		fmt.Println("polling for batch completion... (Ctrl+C to cancel)")
 		for {
-			batch, err = c.apiClient.ReadBatch(ctx, batch.ID)
-			if err != nil {
-				return fmt.Errorf("read batch %s: %w", batch.ID, err)
+			if err = run.Refresh(ctx, c.apiClient); err != nil {
+				return err
 			}
-			fmt.Println(batch.Progress())
-			if batch.IsDone() {
+			fmt.Println(run.Progress())
+			if run.IsDone() {
 				break
 			}
-			time.Sleep(time.Duration(wait) * time.Second)
+			if err = sleep(ctx, time.Duration(wait)*time.Second); err != nil {
+				return err
+			}
 		}
 		// Process the results:
-		return c.processBatchResults(batch.ID)
+		return processRunResults(ctx, c.apiClient, run)
 	}
 
 	// If not waiting, provide instructions for monitoring progress:
 	fmt.Println("Use the following command to monitor progress:")
-	fmt.Printf("gpt batch monitor %s\n", batch.ID)
+	fmt.Printf("gpt batch monitor %s\n", run.Batches[0].ID)
 	fmt.Println("Once the batch is done, use the following command to process the results:")
-	fmt.Printf("gpt chat results %s\n", batch.ID)
+	fmt.Printf("gpt chat results %s\n", run.ID)
 	return nil
 }
 
@@ -495,58 +577,49 @@ func (c *ChatCommand) batchCreate(cmd *cobra.Command, args []string) error {
 // It downloads the results file, processes it, and writes the results
 // to the specified CSV output file.
 func (c *ChatCommand) batchResults(cmd *cobra.Command, args []string) error {
-	return c.processBatchResults(args[0])
+	return processBatchResults(context.Background(), c.apiClient, args[0])
 }
 
-// generateChatRequests generates chat requests from the specified questions/answers.
-func (c *ChatCommand) generateChatRequests(p psy.ChatParameters) ([]psy.Chat, *psy.Table, error) {
-	var chats []psy.Chat
-
-	// Validate the model:
-	if !c.apiClient.ValidModel(context.Background(), p.Model) {
-		return chats, nil, fmt.Errorf("model %s is not a recognized model ID", p.Model)
+// templateArgs returns a validator for commands with a minimum number of
+// arguments, including the prompt and system files, which are omitted when a
+// conversation template is specified with a flag.
+func (c *ChatCommand) templateArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if c.conversation != "" {
+			return cobra.MinimumNArgs(max(n-2, 0))(cmd, args)
+		}
+		return cobra.MinimumNArgs(n)(cmd, args)
 	}
+}
 
-	// Validate the score selection:
-	if !p.ScoreSelect.IsValid() {
-		return chats, nil, fmt.Errorf("invalid score selection (expect first, last, all, or none): %s", p.ScoreSelect)
+// templatePaths returns the prompt and (optional) system file arguments at the
+// specified index, and the remaining arguments. Both are blank if a conversation
+// template is specified instead.
+func (c *ChatCommand) templatePaths(args []string, i int) (promptPath, systemPath string, rest []string) {
+	if c.conversation != "" {
+		return "", "", args
 	}
-
-	// Fetch the system template (optional):
-	var err error
-	var system string
-	if p.SystemFile != "" {
-		system, err = psy.ReadTextFile(p.SystemFile)
-		if err != nil {
-			return chats, nil, fmt.Errorf("system file: %w", err)
-		}
+	promptPath = args[i]
+	if len(args) > i+1 {
+		systemPath = args[i+1]
 	}
+	return promptPath, systemPath, slices.Concat(args[:i], args[min(i+2, len(args)):])
+}
 
-	// Fetch the prompt template:
-	template, err := psy.ReadTextFile(p.PromptFile)
-	if err != nil {
-		return chats, nil, fmt.Errorf("prompt file: %w", err)
+// generateChatRequests generates chat requests from the specified questions/answers.
+// The endpoints identify the API endpoints that the model is expected to support.
+func (c *ChatCommand) generateChatRequests(p psy.ChatParameters, endpoints ...string) ([]psy.Chat, *psy.Table, error) {
+	var chats []psy.Chat
+
+	// Validate the model and request parameters:
+	if err := c.validateParameters(context.Background(), p, endpoints...); err != nil {
+		return chats, nil, err
 	}
 
-	// Read the (optional) question(s):
-	var questions map[string]string
-	var question string
-	var lookupQuestion bool
-	if p.QuestionFile != "" {
-		if strings.Contains(p.QuestionID, "=") {
-			// Lookup the question by ID:
-			question, err = psy.ReadCSVField(p.QuestionFile, p.QuestionID, p.QuestionField)
-			if err != nil {
-				return chats, nil, fmt.Errorf("read question: %w", err)
-			}
-		} else {
-			// Read all the questions:
-			lookupQuestion = true
-			questions, err = psy.ReadCSVFields(p.QuestionFile, p.QuestionID, p.QuestionField)
-			if err != nil {
-				return chats, nil, fmt.Errorf("read questions: %w", err)
-			}
-		}
+	// Read the system message, prompt template, and (optional) question(s):
+	prompter, err := psy.NewPrompter(p)
+	if err != nil {
+		return chats, nil, err
 	}
 
 	// Fetch the table of answers:
@@ -554,24 +627,8 @@ func (c *ChatCommand) generateChatRequests(p psy.ChatParameters) ([]psy.Chat, *p
 	if err != nil {
 		return chats, nil, fmt.Errorf("answer file: %w", err)
 	}
-	if !answers.HasField(p.AnswerField) {
-		return chats, answers, fmt.Errorf("answer field %s not found in %s", p.AnswerField, p.AnswerFile)
-	}
-	if lookupQuestion {
-		if !answers.HasField(p.QuestionID) {
-			return chats, answers, fmt.Errorf("question ID field %s not found in %s", p.QuestionID, p.AnswerFile)
-		}
-		// Validate the question IDs in the answer file:
-		unknownQuestions := make([]string, 0)
-		for _, a := range answers.Records {
-			qid := a[p.QuestionID]
-			if _, ok := questions[qid]; !ok {
-				unknownQuestions = append(unknownQuestions, qid)
-			}
-		}
-		if len(unknownQuestions) > 0 {
-			return chats, answers, fmt.Errorf("unknown question IDs in answer file %s: %s", p.AnswerFile, strings.Join(unknownQuestions, ", "))
-		}
+	if err = prompter.Validate(answers, p.AnswerFile); err != nil {
+		return chats, answers, err
 	}
 
 	// Select one or all records, as specified:
@@ -608,33 +665,46 @@ func (c *ChatCommand) generateChatRequests(p psy.ChatParameters) ([]psy.Chat, *p
 	chats = make([]psy.Chat, 0, len(records))
 	answers.AddField("chatID")
 	for _, a := range records {
-		answer := psy.CleanText(a[p.AnswerField])
-		// Skip blank answers:
-		if answer == "" {
+		// Prepare the messages from the template(s), skipping blank answers:
+		messages, ok, err := prompter.Messages(a)
+		if err != nil {
+			return chats, answers, err
+		}
+		if !ok {
 			a["chatID"] = ""
 			continue
 		}
 		// Generate a unique chat ID:
 		chatID := tuid.NewID().String()
 		a["chatID"] = chatID
-		// Prepare the prompt from the template:
-		var q string
-		if lookupQuestion {
-			qid := a[p.QuestionID]
-			q = questions[qid]
-		} else {
-			q = question
-		}
-		prompt := strings.ReplaceAll(template, "{{question}}", q)
-		prompt = strings.ReplaceAll(prompt, "{{answer}}", answer)
 		// Generate the chat request:
-		chat := psy.NewChat(chatID, system, prompt, c.model, c.temperature, c.maxTokens)
+		chat := psy.NewChatMessages(chatID, messages, p)
 		chats = append(chats, chat)
 	}
 
 	return chats, answers, nil
 }
 
+// validateParameters validates the model, score selection, reasoning effort,
+// and verbosity of the specified chat parameters. It also warns if the model
+// is deprecated or doesn't support the specified API endpoints.
+func (c *ChatCommand) validateParameters(ctx context.Context, p psy.ChatParameters, endpoints ...string) error {
+	if !c.apiClient.ValidModel(ctx, p.Model) {
+		return fmt.Errorf("model %s is not a recognized model ID", p.Model)
+	}
+	warnModel(p.Model, endpoints...)
+	if !p.ScoreSelect.IsValid() {
+		return fmt.Errorf("invalid score selection (expect first, last, all, or none): %s", p.ScoreSelect)
+	}
+	if !p.ReasoningEffort.IsValid() {
+		return fmt.Errorf("invalid reasoning effort (expect minimal, low, medium, or high): %s", p.ReasoningEffort)
+	}
+	if !p.Verbosity.IsValid() {
+		return fmt.Errorf("invalid verbosity (expect low, medium, or high): %s", p.Verbosity)
+	}
+	return nil
+}
+
 // generateChatResponse generates and outputs a chat response from the specified chat request.
 func (c *ChatCommand) generateChatResponse(ctx context.Context, chat psy.Chat, sel psy.Selection) error {
 	// Raw response?
@@ -667,79 +737,92 @@ func (c *ChatCommand) generateChatResponse(ctx context.Context, chat psy.Chat, s
 	return nil
 }
 
-// processBatchResults processes the results of a completed batch operation.
-func (c *ChatCommand) processBatchResults(batchID string) error {
-	// Read the batch and associated response file(s):
-	b, responses, err := c.apiClient.ReadBatchResponses(context.Background(), batchID)
+// processBatchResults processes the results of a completed batch operation, or of
+// all the batches in a run that was split into multiple batches.
+// It's shared by the "chat results" command and the webhook receiver.
+func processBatchResults(ctx context.Context, apiClient *openai.Client, id string) error {
+	run, err := psy.ReadRun(ctx, apiClient, id)
 	if err != nil {
 		return err
 	}
+	if !run.IsDone() {
+		return fmt.Errorf("run %s is not done: %s", run.ID, run.Progress())
+	}
+	return processRunResults(ctx, apiClient, run)
+}
+
+// processRunResults merges the results of a run's batches into its results CSV file.
+func processRunResults(ctx context.Context, apiClient *openai.Client, run psy.Run) error {
+	// Read the associated response file(s):
+	responses, skipped, err := run.ReadResponses(ctx, apiClient)
+	if err != nil {
+		return err
+	}
+	for _, b := range skipped {
+		fmt.Printf("warning: batch %s status %s has no results\n", b.ID, b.Status)
+	}
+	metadata := run.Metadata()
+
+	// Prefer the full chat parameters recorded in the local job registry (if any),
+	// because batch metadata values may be truncated or omitted:
+	if registry, e := openRegistry(); e != nil {
+		fmt.Fprintln(os.Stderr, "warning:", e)
+	} else if p, ok := registry.Parameters(run.ID); ok {
+		maps.Copy(metadata, p.Metadata())
+	}
 
 	// Verify that the incomplete results file exists:
-	outputPath := b.Metadata["output_file"]
+	outputPath := metadata["output_file"]
 	if outputPath == "" {
-		return fmt.Errorf("output_file path not found in batch %s metadata", batchID)
+		return fmt.Errorf("output_file path not found in run %s metadata or job registry", run.ID)
 	}
 	results, err := psy.ReadCSVTable(outputPath)
 	if err != nil {
-		return fmt.Errorf("read incomplete results file %s: %w", outputPath, err)
+		return fmt.Errorf("read incomplete results file %s (see \"gpt batch export\"): %w", outputPath, err)
 	}
 
 	// Identify the score field and selection method:
-	scoreField := b.Metadata["score_field"]
+	scoreField := metadata["score_field"]
 	if scoreField == "" {
 		scoreField = "score"
 	}
-	scoreSelect := b.Metadata["score_select"]
+	scoreSelect := metadata["score_select"]
 	if scoreSelect == "" {
 		scoreSelect = "last"
 	}
 
-	// Add the completion and scores to the results table:
-	var maxScoreCount int
-	for _, record := range results.Records {
-		chatID := record["chatID"]
-		if chatID == "" {
-			continue
-		}
-		response, ok := responses[chatID]
-		if !ok {
-			continue
-		}
-		var completion string
-		var scores []float32
-		if response.HasError() {
-			completion = response.Error.Error()
-		} else {
-			completion = response.Completion()
-			scores = psy.SelectScores(completion, psy.Selection(scoreSelect))
-		}
-		record["completion"] = completion
-		if len(scores) > maxScoreCount {
-			maxScoreCount = len(scores)
-		}
-		for i, score := range scores {
-			field := scoreField
-			if maxScoreCount > 1 {
-				field = fmt.Sprintf("%s%d", scoreField, i+1)
-			}
-			record[field] = fmt.Sprintf("%f", score)
+	// Add the completions and scores to the results table:
+	missing := psy.MergeResponses(results, "chatID", responses, scoreField, psy.Selection(scoreSelect))
+
+	// Write the results to the specified output CSV file:
+	err = results.WriteCSV(outputPath)
+	var failed int
+	for _, response := range responses {
+		if !response.Succeeded() {
+			failed++
 		}
 	}
+	fmt.Printf("completed %d chats (%d failed) in %s\n", len(responses), failed, run.Duration())
+	if err == nil && (failed > 0 || missing > 0) {
+		fmt.Println("Use the following command to retry the failed or missing chats:")
+		fmt.Printf("gpt batch retry %s\n", run.ID)
+	}
+	return err
+}
 
-	// Add field names to the results table:
-	results.AddField("completion")
-	if maxScoreCount == 1 {
-		results.AddField(scoreField)
-	} else if maxScoreCount > 1 {
-		for i := 1; i <= maxScoreCount; i++ {
-			field := fmt.Sprintf("%s%d", scoreField, i)
-			results.AddField(field)
+// abandonRun cancels the batches already created for a run that couldn't be
+// fully submitted, because its results could never be merged. The batch IDs
+// are reported, so that any batch that can't be cancelled can be recovered.
+func abandonRun(ctx context.Context, apiClient *openai.Client, run psy.Run) {
+	if len(run.Batches) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "run %s was not fully submitted: cancelling %d batches\n", run.ID, len(run.Batches))
+	for _, b := range run.Batches {
+		if _, err := apiClient.CancelBatch(ctx, b.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: cancel batch %s (cancel it with \"gpt batch cancel %s\"): %v\n", b.ID, b.ID, err)
+			continue
 		}
+		fmt.Fprintf(os.Stderr, "cancelled batch %s\n", b.ID)
 	}
-
-	// Write the results to the specified output CSV file:
-	err = results.WriteCSV(outputPath)
-	fmt.Printf("completed %d chats (%d failed) in %s\n", b.RequestCounts.Total, b.RequestCounts.Failed, b.Duration())
-	return err
 }
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"gpt/openai"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CleanupCommand is the command for deleting stale files, batches, and models.
+type CleanupCommand struct {
+	apiClient  *openai.Client
+	rootCmd    *cobra.Command
+	baseCmd    *cobra.Command
+	filesCmd   *cobra.Command
+	batchesCmd *cobra.Command
+	modelsCmd  *cobra.Command
+	dryRun     bool
+	yes        bool
+	parallel   int
+}
+
+// cleanupItem is an object to be cleaned up, with a description for the plan.
+type cleanupItem struct {
+	id   string
+	desc string
+}
+
+// NewCleanupCommand creates and initializes the cleanup commands.
+func NewCleanupCommand(apiClient *openai.Client, root *cobra.Command) *CleanupCommand {
+	// Base Command
+	c := &CleanupCommand{
+		apiClient: apiClient,
+		rootCmd:   root,
+	}
+	c.baseCmd = &cobra.Command{
+		Use:   "cleanup",
+		Short: "Clean up stale files, batches, and fine-tuned models",
+		Long: "Clean up stale files, stuck batch operations, and old fine-tuned models, selected with\n" +
+			"filters. The plan is shown, and confirmed before anything is deleted (or cancelled).",
+	}
+	c.baseCmd.PersistentFlags().BoolVarP(&c.dryRun, "dry-run", "n", false, "Show the plan only?")
+	c.baseCmd.PersistentFlags().BoolVarP(&c.yes, "yes", "y", false, "Skip the confirmation?")
+	c.baseCmd.PersistentFlags().IntVarP(&c.parallel, "parallel", "P", 8, "Number of parallel requests")
+	c.rootCmd.AddCommand(c.baseCmd)
+
+	// Files Command
+	// Example: gpt cleanup files -p batch_output -a 720h -s completed
+	c.filesCmd = &cobra.Command{
+		Use:   "files",
+		Short: "Delete stale files",
+		Long: "Delete files selected by purpose, age, file name glob pattern (e.g. \"*-retry*.jsonl\"), and\n" +
+			"the status of the batch operation that references the file (\"none\" for unreferenced files).\n" +
+			"Files referenced by batch operations in progress are never deleted.",
+		Args: cobra.NoArgs,
+		RunE: c.files,
+	}
+	c.filesCmd.Flags().StringP("purpose", "p", "", "Purpose filter (e.g. batch, batch_output, fine-tune)")
+	c.filesCmd.Flags().DurationP("age", "a", 30*24*time.Hour, "Minimum age")
+	c.filesCmd.Flags().StringP("glob", "g", "", "File name glob pattern filter")
+	c.filesCmd.Flags().StringP("batch-status", "s", "", "Referencing batch status filter (e.g. completed, none)")
+	c.baseCmd.AddCommand(c.filesCmd)
+
+	// Batches Command
+	// Example: gpt cleanup batches -a 48h
+	c.batchesCmd = &cobra.Command{
+		Use:   "batches",
+		Short: "Cancel stuck batch operations",
+		Long:  "Cancel batch operations that are still in progress (validating, in_progress, or finalizing) after the minimum age.",
+		Args:  cobra.NoArgs,
+		RunE:  c.batches,
+	}
+	c.batchesCmd.Flags().DurationP("age", "a", 48*time.Hour, "Minimum age")
+	c.batchesCmd.Flags().StringP("status", "s", "", "Status filter (e.g. finalizing)")
+	c.baseCmd.AddCommand(c.batchesCmd)
+
+	// Models Command
+	// Example: gpt cleanup models -a 2160h -g "ft:gpt-4o-mini*:essays:*"
+	c.modelsCmd = &cobra.Command{
+		Use:   "models",
+		Short: "Delete old fine-tuned models",
+		Long:  "Delete fine-tuned models owned by your organization, selected by age and model ID glob pattern.",
+		Args:  cobra.NoArgs,
+		RunE:  c.models,
+	}
+	c.modelsCmd.Flags().DurationP("age", "a", 90*24*time.Hour, "Minimum age")
+	c.modelsCmd.Flags().StringP("glob", "g", "", "Model ID glob pattern filter")
+	c.baseCmd.AddCommand(c.modelsCmd)
+
+	return c
+}
+
+// files is the handler for the "cleanup files" command.
+func (c *CleanupCommand) files(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	purpose, _ := cmd.Flags().GetString("purpose")
+	age, _ := cmd.Flags().GetDuration("age")
+	glob, _ := cmd.Flags().GetString("glob")
+	batchStatus, _ := cmd.Flags().GetString("batch-status")
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern %s: %w", glob, err)
+	}
+
+	// Identify the batch operations that reference each file:
+	var batches []openai.Batch
+	after := ""
+	for {
+		page, hasMore, lastID, err := c.apiClient.ListBatches(ctx, 100, after)
+		if err != nil {
+			return err
+		}
+		batches = append(batches, page...)
+		if !hasMore || lastID == "" {
+			break
+		}
+		after = lastID
+	}
+	refs := openai.FileBatches(batches)
+
+	// Select the files:
+	files, err := c.apiClient.ListFiles(ctx, purpose)
+	if err != nil {
+		return err
+	}
+	filter := openai.CleanupFilter{Cutoff: time.Now().Add(-age).Unix(), Glob: glob, Status: batchStatus}
+	var items []cleanupItem
+	for _, f := range openai.SelectFiles(files, refs, filter) {
+		var used []string
+		for _, b := range refs[f.ID] {
+			used = append(used, b.ID+" "+b.Status)
+		}
+		if len(used) == 0 {
+			used = append(used, "none")
+		}
+		createdAt := time.Unix(f.CreatedAt, 0).Format(time.DateTime)
+		items = append(items, cleanupItem{id: f.ID, desc: fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%s",
+			f.ID, createdAt, f.Purpose, f.FileName, f.Bytes, strings.Join(used, ", "))})
+	}
+	return c.execute(ctx, "delete", "files", "FileID\tCreatedAt\tPurpose\tFileName\tBytes\tBatches", items,
+		c.apiClient.DeleteFile)
+}
+
+// batches is the handler for the "cleanup batches" command.
+func (c *CleanupCommand) batches(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	age, _ := cmd.Flags().GetDuration("age")
+	status, _ := cmd.Flags().GetString("status")
+
+	// Select the stuck batch operations:
+	filter := openai.CleanupFilter{Cutoff: time.Now().Add(-age).Unix(), Status: status}
+	var items []cleanupItem
+	after := ""
+	for {
+		batches, hasMore, lastID, err := c.apiClient.ListBatches(ctx, 100, after)
+		if err != nil {
+			return err
+		}
+		for _, b := range openai.SelectBatches(batches, filter) {
+			items = append(items, cleanupItem{id: b.ID, desc: b.Progress()})
+		}
+		if !hasMore || lastID == "" {
+			break
+		}
+		after = lastID
+	}
+	return c.execute(ctx, "cancel", "batches", "Batch Progress", items, func(ctx context.Context, id string) error {
+		_, err := c.apiClient.CancelBatch(ctx, id)
+		return err
+	})
+}
+
+// models is the handler for the "cleanup models" command.
+func (c *CleanupCommand) models(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	age, _ := cmd.Flags().GetDuration("age")
+	glob, _ := cmd.Flags().GetString("glob")
+	if _, err := path.Match(glob, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern %s: %w", glob, err)
+	}
+
+	// Select the fine-tuned models:
+	models, err := c.apiClient.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	filter := openai.CleanupFilter{Cutoff: time.Now().Add(-age).Unix(), Glob: glob}
+	var items []cleanupItem
+	for _, m := range openai.SelectModels(models, filter) {
+		createdAt := time.Unix(m.CreatedAt, 0).Format(time.DateTime)
+		items = append(items, cleanupItem{id: m.ID, desc: fmt.Sprintf("%s\t%s\t%s", m.ID, createdAt, m.OwnedBy)})
+	}
+	return c.execute(ctx, "delete", "models", "ModelID\tCreatedAt\tOwnedBy", items, c.apiClient.DeleteModel)
+}
+
+// execute shows the cleanup plan, asks for confirmation (unless --yes), and then
+// applies the action to the items in parallel. Failures are reported, but don't
+// stop the cleanup.
+func (c *CleanupCommand) execute(ctx context.Context, action, noun, header string, items []cleanupItem,
+	fn func(ctx context.Context, id string) error) error {
+	// Show the plan:
+	if len(items) == 0 {
+		fmt.Printf("No %s to %s.\n", noun, action)
+		return nil
+	}
+	fmt.Println(header)
+	for _, item := range items {
+		fmt.Println(item.desc)
+	}
+	fmt.Printf("plan: %s %d %s\n", action, len(items), noun)
+	if c.dryRun {
+		return nil
+	}
+	if !c.yes {
+		fmt.Printf("%s %d %s? [y/N] ", strings.ToUpper(action[:1])+action[1:], len(items), noun)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("cancelled")
+			return nil
+		}
+	}
+
+	// Apply the action in parallel:
+	past := map[string]string{"delete": "deleted", "cancel": "cancelled"}[action]
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, max(c.parallel, 1))
+	failed := 0
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := fn(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "error: %s %s: %v\n", action, id, err)
+				return
+			}
+			fmt.Printf("%s: %s\n", past, id)
+		}(item.id)
+	}
+	wg.Wait()
+	fmt.Printf("%s %d of %d %s\n", past, len(items)-failed, len(items), noun)
+	if failed > 0 {
+		return fmt.Errorf("%s %s: %d failed", action, noun, failed)
+	}
+	return nil
+}
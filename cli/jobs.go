@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gpt/openai"
+	"gpt/psy"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// JobsCommand is the command for reviewing the local registry of jobs.
+type JobsCommand struct {
+	apiClient *openai.Client
+	rootCmd   *cobra.Command
+	baseCmd   *cobra.Command
+	listCmd   *cobra.Command
+	showCmd   *cobra.Command
+	syncCmd   *cobra.Command
+}
+
+// NewJobsCommand creates and initializes the jobs commands.
+func NewJobsCommand(apiClient *openai.Client, root *cobra.Command) *JobsCommand {
+	// Base Command
+	c := &JobsCommand{
+		apiClient: apiClient,
+		rootCmd:   root,
+	}
+	c.baseCmd = &cobra.Command{
+		Use:   "jobs",
+		Short: "Review batch and fine-tuning jobs created locally",
+		Long: "Review the local registry of batch and fine-tuning jobs created by gpt on this machine.\n" +
+			"The registry (jobs.json in the user configuration directory, e.g. ~/.config/gpt)\n" +
+			"records each job's chat parameters, file hashes, and results location.",
+	}
+	c.rootCmd.AddCommand(c.baseCmd)
+
+	// List Command
+	// Example: gpt jobs list --status completed --prompt rubric --since 2025-06-01
+	c.listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List registered jobs",
+		Long:  "List registered jobs, most recent first, optionally filtered by kind, status, prompt, or date.",
+		Args:  cobra.NoArgs,
+		RunE:  c.list,
+	}
+	c.listCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
+	c.listCmd.Flags().StringP("kind", "k", "", "Kind filter: batch | fine_tune")
+	c.listCmd.Flags().StringP("status", "s", "", "Status filter (e.g. completed)")
+	c.listCmd.Flags().StringP("prompt", "p", "", "Prompt file filter (substring)")
+	c.listCmd.Flags().String("since", "", "Created on or after date (YYYY-MM-DD)")
+	c.listCmd.Flags().String("until", "", "Created before date (YYYY-MM-DD)")
+	c.listCmd.Flags().IntP("limit", "l", 0, "Limit (0 for all)")
+	c.baseCmd.AddCommand(c.listCmd)
+
+	// Show Command
+	c.showCmd = &cobra.Command{
+		Use:   "show <jobID|runID> [jobID|runID]...",
+		Short: "Show specified registered job(s)",
+		Long:  "Show the full registry record of one or more jobs, specified by job ID or run ID.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.show,
+	}
+	c.baseCmd.AddCommand(c.showCmd)
+
+	// Sync Command
+	c.syncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "Refresh registered job statuses",
+		Long:  "Refresh the statuses (and fine-tuned models) of registered jobs from the OpenAI API.",
+		Args:  cobra.NoArgs,
+		RunE:  c.sync,
+	}
+	c.syncCmd.Flags().BoolP("all", "a", false, "Refresh all jobs? (default: jobs in progress)")
+	c.baseCmd.AddCommand(c.syncCmd)
+
+	return c
+}
+
+// list is the handler for the "jobs list" command.
+func (c *JobsCommand) list(cmd *cobra.Command, args []string) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	limit, _ := cmd.Flags().GetInt("limit")
+	f := psy.JobFilter{}
+	f.Kind, _ = cmd.Flags().GetString("kind")
+	f.Status, _ = cmd.Flags().GetString("status")
+	f.Prompt, _ = cmd.Flags().GetString("prompt")
+	var err error
+	if since, _ := cmd.Flags().GetString("since"); since != "" {
+		if f.Since, err = time.ParseInLocation(time.DateOnly, since, time.Local); err != nil {
+			return fmt.Errorf("invalid since date %s: %w", since, err)
+		}
+	}
+	if until, _ := cmd.Flags().GetString("until"); until != "" {
+		if f.Until, err = time.ParseInLocation(time.DateOnly, until, time.Local); err != nil {
+			return fmt.Errorf("invalid until date %s: %w", until, err)
+		}
+	}
+
+	// Select the registered jobs:
+	r, err := openRegistry()
+	if err != nil {
+		return err
+	}
+	jobs := r.List(f)
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	if len(jobs) == 0 {
+		fmt.Println("No jobs found.")
+		return nil
+	}
+
+	// Print the jobs:
+	if verbose {
+		j, e := json.MarshalIndent(jobs, "", "  ")
+		if e != nil {
+			return fmt.Errorf("error marshalling Jobs JSON: %w", e)
+		}
+		fmt.Println(string(j))
+		return nil
+	}
+	fmt.Println("JobID\tKind\tCreatedAt\tStatus\tModel\tPrompt\tResult")
+	for _, job := range jobs {
+		fmt.Println(job.String())
+	}
+	return nil
+}
+
+// show is the handler for the "jobs show" command.
+func (c *JobsCommand) show(cmd *cobra.Command, args []string) error {
+	r, err := openRegistry()
+	if err != nil {
+		return err
+	}
+	for _, id := range args {
+		jobs := r.Run(id)
+		if len(jobs) == 0 {
+			return fmt.Errorf("job %s not found in registry %s", id, r.Path)
+		}
+		for _, job := range jobs {
+			j, e := json.MarshalIndent(job, "", "  ")
+			if e != nil {
+				return fmt.Errorf("error marshalling Job JSON: %w", e)
+			}
+			fmt.Println(string(j))
+		}
+	}
+	return nil
+}
+
+// sync is the handler for the "jobs sync" command.
+func (c *JobsCommand) sync(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	all, _ := cmd.Flags().GetBool("all")
+	r, err := openRegistry()
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, job := range r.Jobs {
+		if job.IsDone() && !all {
+			continue
+		}
+		switch job.Kind {
+		case psy.JobBatch:
+			b, e := c.apiClient.ReadBatch(ctx, job.ID)
+			if e != nil {
+				fmt.Fprintln(os.Stderr, "warning:", e)
+				continue
+			}
+			r.Put(psy.NewBatchJob(b, nil))
+		case psy.JobFineTune:
+			f, e := c.apiClient.ReadFineTune(ctx, job.ID)
+			if e != nil {
+				fmt.Fprintln(os.Stderr, "warning:", e)
+				continue
+			}
+			r.Put(psy.NewFineTuneJob(f))
+		default:
+			continue
+		}
+		updated, _ := r.Get(job.ID)
+		if updated.Status != job.Status {
+			fmt.Printf("%s: %s -> %s\n", job.ID, job.Status, updated.Status)
+		}
+		count++
+	}
+	if err = r.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("refreshed %d of %d registered jobs\n", count, len(r.Jobs))
+	return nil
+}
+
+// openRegistry opens the local job registry.
+func openRegistry() (*psy.Registry, error) {
+	path, err := psy.DefaultRegistryPath()
+	if err != nil {
+		return nil, err
+	}
+	return psy.OpenRegistry(path)
+}
+
+// registerJobs records jobs in the local job registry. Failures are reported
+// as warnings, because the registry is a convenience, not a requirement.
+func registerJobs(jobs ...psy.Job) {
+	r, err := openRegistry()
+	if err == nil {
+		for _, job := range jobs {
+			r.Put(job)
+		}
+		err = r.Save()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning:", err)
+	}
+}
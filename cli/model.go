@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"gpt/openai"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -17,6 +19,7 @@ type ModelCommand struct {
 	baseCmd   *cobra.Command
 	listCmd   *cobra.Command
 	readCmd   *cobra.Command
+	infoCmd   *cobra.Command
 	deleteCmd *cobra.Command
 	raw       bool
 }
@@ -56,6 +59,20 @@ func NewModelCommand(apiClient *openai.Client, root *cobra.Command) *ModelComman
 	}
 	c.baseCmd.AddCommand(c.readCmd)
 
+	// Info Command
+	c.infoCmd = &cobra.Command{
+		Use:   "info <modelID> [modelID]...",
+		Short: "Show catalog information for model(s)",
+		Long: "Show the context window, maximum output, modalities, endpoints, pricing, and deprecation\n" +
+			"status of one or more models, specified by ID, from the local model catalog. The embedded\n" +
+			"catalog is supplemented by models.json in the gpt user config directory (e.g.\n" +
+			"~/.config/gpt/models.json), or the file specified by the GPT_MODEL_CATALOG variable.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: c.info,
+	}
+	c.infoCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
+	c.baseCmd.AddCommand(c.infoCmd)
+
 	// Delete Command
 	c.deleteCmd = &cobra.Command{
 		Use:   "delete <modelID> [modelID]...",
@@ -141,6 +158,59 @@ func (c *ModelCommand) read(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// info shows the catalog information for the specified model(s).
+func (c *ModelCommand) info(cmd *cobra.Command, args []string) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	for _, modelID := range args {
+		m, ok := openai.Registry.Lookup(modelID)
+		if !ok {
+			return fmt.Errorf("model %s not found in the model catalog", modelID)
+		}
+		if verbose || c.raw {
+			j, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error marshalling JSON model info: %w", err)
+			}
+			fmt.Println(string(j))
+			continue
+		}
+		fmt.Printf("Model:\t\t%s\n", m.ID)
+		if modelID != m.ID {
+			fmt.Printf("Requested:\t%s\n", modelID)
+		}
+		if m.Description != "" {
+			fmt.Printf("Description:\t%s\n", m.Description)
+		}
+		if m.ContextWindow > 0 {
+			fmt.Printf("Context:\t%d tokens\n", m.ContextWindow)
+		}
+		if m.MaxOutputTokens > 0 {
+			fmt.Printf("Max Output:\t%d tokens\n", m.MaxOutputTokens)
+		}
+		fmt.Printf("Modalities:\t%s -> %s\n", strings.Join(m.InputModalities, ", "), strings.Join(m.OutputModalities, ", "))
+		fmt.Printf("Endpoints:\t%s\n", strings.Join(m.Endpoints, ", "))
+		if m.Reasoning {
+			fmt.Printf("Reasoning:\tyes (verbosity: %t)\n", m.Verbosity)
+		}
+		if m.FineTuningContext > 0 {
+			fmt.Printf("Fine-Tuning:\t%d tokens per example\n", m.FineTuningContext)
+		}
+		p := m.Pricing
+		fmt.Printf("Pricing:\t$%.3f input, $%.3f cached input, $%.3f output per 1M tokens\n", p.Input, p.CachedInput, p.Output)
+		if p.BatchInput > 0 {
+			fmt.Printf("Batch Pricing:\t$%.3f input, $%.3f output per 1M tokens\n", p.BatchInput, p.BatchOutput)
+		}
+		if p.Training > 0 {
+			fmt.Printf("Training:\t$%.3f per 1M tokens\n", p.Training)
+		}
+		for _, w := range m.Warnings(time.Now()) {
+			fmt.Printf("Warning:\t%s\n", w)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
 // delete the specified model(s).
 func (c *ModelCommand) delete(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
@@ -159,3 +229,11 @@ func (c *ModelCommand) delete(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// warnModel prints warnings about the suitability of the specified model for
+// use with the specified API endpoints, based on the local model catalog.
+func warnModel(modelID string, endpoints ...string) {
+	for _, w := range openai.Registry.Warnings(modelID, endpoints...) {
+		fmt.Fprintln(os.Stderr, "warning:", w)
+	}
+}
@@ -17,9 +17,14 @@ type RootCommand struct {
 	docCmd    *cobra.Command
 	batchCmd  *BatchCommand
 	chatCmd   *ChatCommand
+	cleanCmd  *CleanupCommand
 	fileCmd   *FileCommand
+	jobsCmd   *JobsCommand
 	modelCmd  *ModelCommand
 	tuneCmd   *TuneCommand
+	usageCmd  *UsageCommand
+	vectorCmd *VectorCommand
+	hookCmd   *WebhookCommand
 }
 
 // NewRootCommand creates and initializes the root command and all its subcommands.
@@ -70,9 +75,14 @@ func NewRootCommand(apiClient *openai.Client) *RootCommand {
 	// Other Commands
 	c.batchCmd = NewBatchCommand(apiClient, c.rootCmd)
 	c.chatCmd = NewChatCommand(apiClient, c.rootCmd)
+	c.cleanCmd = NewCleanupCommand(apiClient, c.rootCmd)
 	c.fileCmd = NewFileCommand(apiClient, c.rootCmd)
+	c.jobsCmd = NewJobsCommand(apiClient, c.rootCmd)
 	c.modelCmd = NewModelCommand(apiClient, c.rootCmd)
 	c.tuneCmd = NewTuneCommand(apiClient, c.rootCmd)
+	c.usageCmd = NewUsageCommand(apiClient, c.rootCmd)
+	c.vectorCmd = NewVectorCommand(apiClient, c.rootCmd)
+	c.hookCmd = NewWebhookCommand(apiClient, c.rootCmd)
 
 	return c
 }
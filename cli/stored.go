@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gpt/openai"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// StoredCommand is the command for reviewing stored chat completions.
+type StoredCommand struct {
+	apiClient   *openai.Client
+	chatCmd     *cobra.Command
+	baseCmd     *cobra.Command
+	listCmd     *cobra.Command
+	readCmd     *cobra.Command
+	messagesCmd *cobra.Command
+	deleteCmd   *cobra.Command
+	raw         bool
+}
+
+// NewStoredCommand creates and initializes the stored chat completions commands.
+func NewStoredCommand(apiClient *openai.Client, chat *cobra.Command) *StoredCommand {
+	// Base Command
+	c := &StoredCommand{
+		apiClient: apiClient,
+		chatCmd:   chat,
+	}
+	c.baseCmd = &cobra.Command{
+		Use:   "stored",
+		Short: "Review stored chat completions",
+		Long: "Review chat completions stored with the --store flag, which are tagged with the run's\n" +
+			"parameters (e.g. output_file, model) and chat_id as metadata.",
+	}
+	c.baseCmd.PersistentFlags().BoolVarP(&c.raw, "raw", "r", false, "Raw OpenAI Response?")
+	c.chatCmd.AddCommand(c.baseCmd)
+
+	// List Command
+	// Example: gpt chat stored list -M output_file=examples/scores.csv -m gpt-4o-mini
+	c.listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List stored chat completions",
+		Long:  "List stored chat completions, optionally filtered by model and metadata.",
+		Args:  cobra.NoArgs,
+		RunE:  c.list,
+	}
+	c.listCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
+	c.listCmd.Flags().StringP("model", "m", "", "Model ID filter (optional)")
+	c.listCmd.Flags().StringToStringP("metadata", "M", nil, "Metadata key=value filter (optional)")
+	c.listCmd.Flags().StringP("order", "o", "desc", "Order by creation time: asc | desc")
+	c.listCmd.Flags().IntP("limit", "l", 20, "Limit")
+	c.listCmd.Flags().StringP("after", "a", "", "After (last ID received)")
+	c.baseCmd.AddCommand(c.listCmd)
+
+	// Read Command
+	c.readCmd = &cobra.Command{
+		Use:   "read <completionID> [completionID]...",
+		Short: "Read specified stored chat completion(s)",
+		Long:  "Read one or more stored chat completions, specified by ID.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.read,
+	}
+	c.baseCmd.AddCommand(c.readCmd)
+
+	// Messages Command
+	c.messagesCmd = &cobra.Command{
+		Use:   "messages <completionID>",
+		Short: "List the messages of a stored chat completion",
+		Long:  "List the request messages (e.g. system and user prompts) of a stored chat completion.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  c.messages,
+	}
+	c.baseCmd.AddCommand(c.messagesCmd)
+
+	// Delete Command
+	c.deleteCmd = &cobra.Command{
+		Use:   "delete <completionID> [completionID]...",
+		Short: "Delete specified stored chat completion(s)",
+		Long:  "Delete one or more stored chat completions, specified by ID.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.delete,
+	}
+	c.baseCmd.AddCommand(c.deleteCmd)
+
+	return c
+}
+
+// list is the handler for the "chat stored list" command.
+func (c *StoredCommand) list(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	model, _ := cmd.Flags().GetString("model")
+	metadata, _ := cmd.Flags().GetStringToString("metadata")
+	order, _ := cmd.Flags().GetString("order")
+	limit, _ := cmd.Flags().GetInt("limit")
+	after, _ := cmd.Flags().GetString("after")
+	filter := openai.StoredCompletionFilter{
+		Model:    model,
+		Metadata: metadata,
+		Order:    order,
+		Limit:    limit,
+		After:    after,
+	}
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		body, e := c.apiClient.ListStoredCompletionsRaw(ctx, filter)
+		if body != nil {
+			fmt.Println(string(body))
+		}
+		return e
+	}
+
+	// Retrieve the stored completions
+	completions, hasMore, lastID, err := c.apiClient.ListStoredCompletions(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if len(completions) == 0 {
+		fmt.Println("No stored completions found.")
+		return nil
+	}
+
+	// Print the stored completions
+	if verbose {
+		j, err := json.MarshalIndent(completions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling ChatResponses JSON: %w", err)
+		}
+		fmt.Println(string(j))
+	} else {
+		fmt.Println("CompletionID\tCreatedAt\tModel\tChatID\tOutputFile\tTokens")
+		for _, r := range completions {
+			createdAt := time.Unix(r.CreatedAt, 0).Format(time.DateTime)
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\t%d\n", r.ID, createdAt, r.Model,
+				r.Metadata["chat_id"], r.Metadata["output_file"], r.Usage.TotalTokens)
+		}
+	}
+	if hasMore {
+		fmt.Printf("More results available. Use --limit=%d --after=%s to retrieve.\n", limit, lastID)
+	}
+	return nil
+}
+
+// read is the handler for the "chat stored read" command.
+func (c *StoredCommand) read(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	for _, id := range args {
+		// Retrieve the raw OpenAI response?
+		if c.raw {
+			body, e := c.apiClient.ReadStoredCompletionRaw(ctx, id)
+			if body != nil {
+				fmt.Print(string(body))
+			}
+			if e != nil {
+				return e
+			}
+			continue
+		}
+		completion, e := c.apiClient.ReadStoredCompletion(ctx, id)
+		if e != nil {
+			return e
+		}
+		j, err := json.MarshalIndent(completion, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling ChatResponse JSON: %w", err)
+		}
+		fmt.Println(string(j))
+	}
+	return nil
+}
+
+// messages is the handler for the "chat stored messages" command.
+func (c *StoredCommand) messages(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	id := args[0]
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		body, e := c.apiClient.ListStoredMessagesRaw(ctx, id, 100, "")
+		if body != nil {
+			fmt.Println(string(body))
+		}
+		return e
+	}
+
+	// Print all the messages
+	after := ""
+	for {
+		messages, hasMore, lastID, err := c.apiClient.ListStoredMessages(ctx, id, 100, after)
+		if err != nil {
+			return err
+		}
+		for _, m := range messages {
+			msg := openai.Message{Role: m.Role, Content: m.Content}
+			fmt.Print(msg.String())
+		}
+		if !hasMore || lastID == "" {
+			return nil
+		}
+		after = lastID
+	}
+}
+
+// delete is the handler for the "chat stored delete" command.
+func (c *StoredCommand) delete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	for _, id := range args {
+		body, err := c.apiClient.DeleteStoredCompletionRaw(ctx, id)
+		if err != nil {
+			return err
+		}
+		if body != nil && c.raw {
+			fmt.Print(string(body))
+		} else {
+			fmt.Println("Deleted:", id)
+		}
+	}
+	return nil
+}
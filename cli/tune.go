@@ -1,10 +1,22 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"gpt/openai"
+	"gpt/psy"
+	"maps"
+	"math"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -20,6 +32,16 @@ type TuneCommand struct {
 	eventsCmd *cobra.Command
 	createCmd *cobra.Command
 	cancelCmd *cobra.Command
+	pauseCmd  *cobra.Command
+	resumeCmd *cobra.Command
+	checksCmd *cobra.Command
+	metricCmd *cobra.Command
+	monitCmd  *cobra.Command
+	prepCmd   *cobra.Command
+	validCmd  *cobra.Command
+	evalCmd   *cobra.Command
+	distCmd   *cobra.Command
+	prefCmd   *cobra.Command
 	raw       bool
 }
 
@@ -48,6 +70,7 @@ func NewTuneCommand(apiClient *openai.Client, root *cobra.Command) *TuneCommand
 	c.listCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
 	c.listCmd.Flags().IntP("limit", "l", 20, "Limit")
 	c.listCmd.Flags().StringP("after", "a", "", "After (last ID received)")
+	c.listCmd.Flags().StringToStringP("metadata", "M", nil, "Metadata key=value filter (optional)")
 	c.baseCmd.AddCommand(c.listCmd)
 
 	// Read Command
@@ -77,12 +100,23 @@ func NewTuneCommand(apiClient *openai.Client, root *cobra.Command) *TuneCommand
 	c.createCmd = &cobra.Command{
 		Use:   "create <trainingFileID> [validationFileID]",
 		Short: "Create a fine-tuning job",
-		Long:  "Create a fine-tuning job using the provided file ID(s).",
-		Args:  cobra.MinimumNArgs(1),
-		RunE:  c.create,
+		Long: "Create a fine-tuning job using the provided file ID(s). The method may be supervised\n" +
+			"(example conversations), dpo (preference pairs), or reinforcement (with a grader). The\n" +
+			"hyperparameters may be \"auto\" (the default) or a specific value.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: c.create,
 	}
-	c.createCmd.Flags().StringP("base", "b", "gpt-3.5-turbo", "Base model to fine-tune")
+	c.createCmd.Flags().StringP("base", "b", "gpt-4.1-mini-2025-04-14", "Base model to fine-tune")
+	c.createCmd.Flags().StringP("method", "m", openai.MethodSupervised, "Method: supervised | dpo | reinforcement")
+	c.createCmd.Flags().StringP("epochs", "e", "", "Number of epochs: auto or integer")
+	c.createCmd.Flags().String("batch-size", "", "Batch size: auto or integer")
+	c.createCmd.Flags().String("lr-multiplier", "", "Learning rate multiplier: auto or number")
+	c.createCmd.Flags().String("beta", "", "DPO beta: auto or number")
+	c.createCmd.Flags().StringP("grader", "g", "", "Grader JSON file (reinforcement only)")
 	c.createCmd.Flags().StringP("suffix", "s", "", "Name suffix for the fine-tuned model")
+	c.createCmd.Flags().Int("seed", 0, "Seed for reproducibility (optional)")
+	c.createCmd.Flags().StringToStringP("metadata", "M", nil, "Metadata key=value pairs (optional)")
+	c.createCmd.Flags().String("wandb", "", "Weights and Biases project for metrics (optional)")
 	c.baseCmd.AddCommand(c.createCmd)
 
 	// Cancel Command
@@ -95,6 +129,233 @@ func NewTuneCommand(apiClient *openai.Client, root *cobra.Command) *TuneCommand
 	}
 	c.baseCmd.AddCommand(c.cancelCmd)
 
+	// Pause Command
+	c.pauseCmd = &cobra.Command{
+		Use:   "pause <jobID> [jobID]...",
+		Short: "Pause specified fine-tuning job(s)",
+		Long:  "Pause one or more running fine-tuning jobs, specified by ID. A checkpoint is saved, and the job may be resumed.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.pause,
+	}
+	c.baseCmd.AddCommand(c.pauseCmd)
+
+	// Resume Command
+	c.resumeCmd = &cobra.Command{
+		Use:   "resume <jobID> [jobID]...",
+		Short: "Resume specified fine-tuning job(s)",
+		Long:  "Resume one or more paused fine-tuning jobs, specified by ID.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.resume,
+	}
+	c.baseCmd.AddCommand(c.resumeCmd)
+
+	// Checkpoints Command
+	// Example: gpt chat prompt -m $(gpt tune checkpoints ftjob-abc123 --best) ...
+	c.checksCmd = &cobra.Command{
+		Use:   "checkpoints <jobID>",
+		Short: "List checkpoints for a fine-tuning job",
+		Long: "List the model checkpoints saved by a fine-tuning job, with their training and validation\n" +
+			"metrics. Use --best to print only the model ID of the checkpoint with the lowest validation\n" +
+			"loss, for use with the chat commands.",
+		Args: cobra.ExactArgs(1),
+		RunE: c.checkpoints,
+	}
+	c.checksCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
+	c.checksCmd.Flags().BoolP("best", "b", false, "Print only the best checkpoint model ID?")
+	c.checksCmd.Flags().IntP("limit", "l", 10, "Limit")
+	c.checksCmd.Flags().StringP("after", "a", "", "After (last ID received)")
+	c.baseCmd.AddCommand(c.checksCmd)
+
+	// Metrics Command
+	// Example: gpt tune metrics ftjob-abc123 ftjob-def456 -o curves.csv
+	c.metricCmd = &cobra.Command{
+		Use:   "metrics <jobID> [jobID]...",
+		Short: "Show learning curves for fine-tuning job(s)",
+		Long: "Show the training and validation loss curves of one or more fine-tuning jobs, from their\n" +
+			"result files (or from metrics events, while a job is running). Several jobs are compared\n" +
+			"side by side, to spot overfitting: a validation loss that rises above its minimum, or a\n" +
+			"large gap between validation and training loss. The step metrics may be exported as CSV.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: c.metrics,
+	}
+	c.metricCmd.Flags().StringP("output", "o", "", "Output CSV file for the step metrics (optional)")
+	c.metricCmd.Flags().BoolP("steps", "s", false, "Print the step metrics?")
+	c.metricCmd.Flags().IntP("width", "w", 60, "Width of the loss sparklines")
+	c.baseCmd.AddCommand(c.metricCmd)
+
+	// Monitor Command
+	// Example: gpt tune monitor ftjob-abc123 --save-env .env -x 'gpt chat prompt -m $FINE_TUNED_MODEL ...'
+	c.monitCmd = &cobra.Command{
+		Use:   "monitor <jobID>",
+		Short: "Monitor specified fine-tuning job",
+		Long: "Monitor the progress of a fine-tuning job, logging status changes and new events until it\n" +
+			"is done. When the job succeeds, the fine-tuned model ID may be saved to an env file (e.g.\n" +
+			".env) and a shell command may be run, with the FINE_TUNED_MODEL and FINE_TUNE_JOB_ID\n" +
+			"environment variables set. The command fails if the job fails or is cancelled, if the job\n" +
+			"can't be read (e.g. an unknown job ID, or repeated network errors), or if it's interrupted.",
+		Args: cobra.ExactArgs(1),
+		RunE: c.monitor,
+	}
+	c.monitCmd.Flags().IntP("wait", "w", 30, "Wait interval (seconds)")
+	c.monitCmd.Flags().StringP("exec", "x", "", "Shell command to run when the job succeeds (optional)")
+	c.monitCmd.Flags().String("save-env", "", "Env file in which to save the fine-tuned model ID (optional)")
+	c.monitCmd.Flags().String("env-key", "GPT_FINE_TUNED_MODEL", "Env file variable name for the model ID")
+	c.baseCmd.AddCommand(c.monitCmd)
+
+	// Prepare Command
+	// Example: gpt tune prepare examples/scored.csv examples/questions.csv -p examples/prompt.txt -s examples/system.txt -a answer -q question -Q qid -c score
+	c.prepCmd = &cobra.Command{
+		Use:   "prepare <answerFile> [questionFile]",
+		Short: "Prepare fine-tuning data from a CSV file",
+		Long: "Prepare training and validation JSONL files of example conversations from a CSV file of\n" +
+			"answers with target completions (e.g. human scores). Prompts are generated from the system\n" +
+			"file and prompt template (or a conversation template), as with the chat commands. The\n" +
+			"completion is taken from a field, or generated from a completion template with {{field}}\n" +
+			"placeholders. The examples are split by ratio, stratified by a field (the completion field,\n" +
+			"by default), with a reproducible seed.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: c.prepare,
+	}
+	c.prepCmd.Flags().StringP("prompt", "p", "", "Prompt template file (or conversation)")
+	c.prepCmd.Flags().StringP("system", "s", "", "System message file (optional)")
+	c.prepCmd.Flags().String("conversation", "", "Conversation template file, instead of prompt and system files")
+	c.prepCmd.Flags().StringP("question-id", "Q", "", "Question ID (optional, name | name=value)")
+	c.prepCmd.Flags().StringP("question-field", "q", "", "Question field name (optional)")
+	c.prepCmd.Flags().StringP("answer-field", "a", "", "Answer field name (required)")
+	c.prepCmd.Flags().StringP("completion-field", "c", "", "Completion field name (e.g. score)")
+	c.prepCmd.Flags().StringP("completion-template", "t", "", "Completion template file, with {{field}} placeholders")
+	c.prepCmd.Flags().Float64P("validation", "V", 0.2, "Fraction of examples for validation")
+	c.prepCmd.Flags().String("stratify", "", "Stratification field name (default: completion field)")
+	c.prepCmd.Flags().Int64("seed", 0, "Seed for the random split (default: random)")
+	c.prepCmd.Flags().StringP("output", "o", "", "Output file name prefix (default: answer file name)")
+	c.prepCmd.MarkFlagsOneRequired("prompt", "conversation")
+	c.prepCmd.MarkFlagsMutuallyExclusive("prompt", "conversation")
+	c.prepCmd.MarkFlagRequired("answer-field")
+	c.baseCmd.AddCommand(c.prepCmd)
+
+	// Validate Command
+	// Example: gpt tune validate scored-train.jsonl scored-valid.jsonl -b gpt-4.1-mini-2025-04-14 -e 3
+	c.validCmd = &cobra.Command{
+		Use:   "validate <trainingFile> [validationFile]",
+		Short: "Validate fine-tuning data files",
+		Long: "Validate local training (and validation) JSONL files of example conversations, or of DPO\n" +
+			"preference pairs, before they're uploaded: role ordering, assistant messages, empty content,\n" +
+			"duplicates, example tokens within the base model's limit, and overlap between the files.\n" +
+			"The token distribution and the training cost are estimated. The command fails if any\n" +
+			"errors are found.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: c.validate,
+	}
+	c.validCmd.Flags().StringP("base", "b", "gpt-4.1-mini-2025-04-14", "Base model to fine-tune")
+	c.validCmd.Flags().StringP("epochs", "e", "auto", "Number of epochs: auto or integer")
+	c.baseCmd.AddCommand(c.validCmd)
+
+	// Evaluate Command
+	// Example: gpt tune evaluate ftjob-abc123 holdout.csv questions.csv -p prompt.txt -s system.txt -a answer -q question -Q qid -H score
+	c.evalCmd = &cobra.Command{
+		Use:   "evaluate <jobID|model> <answerFile> [questionFile]",
+		Short: "Evaluate a fine-tuned model on holdout data",
+		Long: "Score holdout answers with a fine-tuned model (specified by job ID or model ID) and with one\n" +
+			"or more comparison models (the base model, by default), using the same prompts as the chat\n" +
+			"commands. Each model's agreement with the human scores is reported: MAE, RMSE, Pearson and\n" +
+			"Spearman correlations, quadratic weighted kappa, and a confusion matrix. The completions and\n" +
+			"scores of every model are written to a single comparison CSV file.",
+		Args: cobra.RangeArgs(2, 3),
+		RunE: c.evaluate,
+	}
+	c.evalCmd.Flags().StringP("prompt", "p", "", "Prompt template file (or conversation)")
+	c.evalCmd.Flags().StringP("system", "s", "", "System message file (optional)")
+	c.evalCmd.Flags().String("conversation", "", "Conversation template file, instead of prompt and system files")
+	c.evalCmd.Flags().StringP("question-id", "Q", "", "Question ID (optional, name | name=value)")
+	c.evalCmd.Flags().StringP("question-field", "q", "", "Question field name (optional)")
+	c.evalCmd.Flags().StringP("answer-field", "a", "", "Answer field name (required)")
+	c.evalCmd.Flags().StringP("human-field", "H", "score", "Human score field name")
+	c.evalCmd.Flags().StringSliceP("compare", "c", nil, "Comparison model IDs (default: the base model)")
+	c.evalCmd.Flags().StringP("score-select", "S", "last", "Score selection: first | last")
+	c.evalCmd.Flags().Float32P("temperature", "T", 1.0, "Temperature for sampling")
+	c.evalCmd.Flags().IntP("max-tokens", "t", 0, "Maximum number of tokens to generate")
+	c.evalCmd.Flags().IntP("batch-size", "b", 20, "Concurrent request batch size")
+	c.evalCmd.Flags().StringP("output", "o", "", "Output CSV file (default: <answerFile>-eval.csv)")
+	c.evalCmd.MarkFlagsOneRequired("prompt", "conversation")
+	c.evalCmd.MarkFlagsMutuallyExclusive("prompt", "conversation")
+	c.evalCmd.MarkFlagRequired("answer-field")
+	c.baseCmd.AddCommand(c.evalCmd)
+
+	// Distill Command
+	// Example: gpt tune distill scores.csv -H human_score -d 0.5 --create -b gpt-4.1-nano-2025-04-14
+	c.distCmd = &cobra.Command{
+		Use:   "distill <resultsFile> [questionFile]",
+		Short: "Prepare fine-tuning data from chat results",
+		Long: "Prepare training and validation JSONL files from the results of a chat parallel or chat batch\n" +
+			"run, so that a smaller model can be fine-tuned to reproduce a larger model's completions. Each\n" +
+			"row's request is reconstructed from the run's prompt and system files, or its conversation\n" +
+			"template (recorded in the local job registry, or specified with flags), with the recorded\n" +
+			"completion as the target; the command fails if the template files are missing, or differ\n" +
+			"from those registered for the run. Rows without a chat ID or a valid score, or whose score\n" +
+			"disagrees with a human score by more than the maximum difference, are excluded. Optionally,\n" +
+			"the files are uploaded and a fine-tuning job is created.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: c.distill,
+	}
+	c.distCmd.Flags().StringP("run", "R", "", "Batch ID or run ID of the chat parameters (default: by results file)")
+	c.distCmd.Flags().StringP("prompt", "p", "", "Prompt template file")
+	c.distCmd.Flags().StringP("system", "s", "", "System message file")
+	c.distCmd.Flags().String("conversation", "", "Conversation template file, instead of prompt and system files")
+	c.distCmd.MarkFlagsMutuallyExclusive("prompt", "conversation")
+	c.distCmd.Flags().StringP("question-id", "Q", "", "Question ID (name | name=value)")
+	c.distCmd.Flags().StringP("question-field", "q", "", "Question field name")
+	c.distCmd.Flags().StringP("answer-field", "a", "", "Answer field name")
+	c.distCmd.Flags().String("score-field", "", "Model score field name (default: the run's score field)")
+	c.distCmd.Flags().StringP("human-field", "H", "", "Human score field name (optional)")
+	c.distCmd.Flags().Float64P("max-diff", "d", 0, "Maximum difference between the model and human scores")
+	c.distCmd.Flags().Float64P("validation", "V", 0.2, "Fraction of examples for validation")
+	c.distCmd.Flags().Int64("seed", 0, "Seed for the random split (default: random)")
+	c.distCmd.Flags().StringP("output", "o", "", "Output file name prefix (default: <resultsFile>-distill)")
+	c.distCmd.Flags().Bool("create", false, "Upload the files and create a fine-tuning job?")
+	c.distCmd.Flags().StringP("base", "b", "gpt-4.1-mini-2025-04-14", "Base model to fine-tune")
+	c.distCmd.Flags().String("suffix", "", "Name suffix for the fine-tuned model")
+	c.baseCmd.AddCommand(c.distCmd)
+
+	// Prefer Command
+	// Example: gpt tune prefer answers.csv questions.csv -p prompt.txt -s system.txt -a answer -q question -Q qid -n 4 -j judge.txt
+	c.prefCmd = &cobra.Command{
+		Use:   "prefer <answerFile> [questionFile]",
+		Short: "Prepare DPO preference pairs from sampled completions",
+		Long: "Prepare training and validation JSONL files of preference pairs for DPO fine-tuning. Several\n" +
+			"completions are sampled for each answer, and written to a samples CSV file (completion1,\n" +
+			"completion2, ...). The preferred and non-preferred completions are picked by a judge prompt,\n" +
+			"whose reply should end with a line giving the numbers of the best and worst responses (e.g.\n" +
+			"\"2,3\"). The judge template may use {{prompt}} (the final user message), {{responses}},\n" +
+			"and {{field}} placeholders. Alternatively, raters may record their picks in the samples CSV\n" +
+			"file, which is then used as the answer file with the preferred and rejected field flags.\n" +
+			"Prompts may be generated from a conversation template instead of the prompt and system\n" +
+			"files. Each pair is validated before it's written.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: c.prefer,
+	}
+	c.prefCmd.Flags().StringP("prompt", "p", "", "Prompt template file (or conversation)")
+	c.prefCmd.Flags().StringP("system", "s", "", "System message file (optional)")
+	c.prefCmd.Flags().String("conversation", "", "Conversation template file, instead of prompt and system files")
+	c.prefCmd.Flags().StringP("question-id", "Q", "", "Question ID (optional, name | name=value)")
+	c.prefCmd.Flags().StringP("question-field", "q", "", "Question field name (optional)")
+	c.prefCmd.Flags().StringP("answer-field", "a", "", "Answer field name (required)")
+	c.prefCmd.Flags().StringP("model", "m", "gpt-4.1-mini", "Model ID for sampling completions")
+	c.prefCmd.Flags().IntP("samples", "n", 4, "Number of completions to sample per answer")
+	c.prefCmd.Flags().Float32P("temperature", "T", 1.0, "Temperature for sampling")
+	c.prefCmd.Flags().IntP("max-tokens", "t", 0, "Maximum number of tokens to generate")
+	c.prefCmd.Flags().StringP("judge", "j", "", "Judge prompt template file (optional)")
+	c.prefCmd.Flags().String("judge-model", "", "Model ID for the judge (default: the sampling model)")
+	c.prefCmd.Flags().String("preferred-field", "", "Rater field with the preferred completion number")
+	c.prefCmd.Flags().String("rejected-field", "", "Rater field with the non-preferred completion number")
+	c.prefCmd.Flags().IntP("batch-size", "b", 20, "Concurrent request batch size")
+	c.prefCmd.Flags().Float64P("validation", "V", 0.2, "Fraction of pairs for validation")
+	c.prefCmd.Flags().Int64("seed", 0, "Seed for the random split (default: random)")
+	c.prefCmd.Flags().StringP("output", "o", "", "Output file name prefix (default: <answerFile>-prefer)")
+	c.prefCmd.MarkFlagsOneRequired("prompt", "conversation")
+	c.prefCmd.MarkFlagsMutuallyExclusive("prompt", "conversation")
+	c.prefCmd.MarkFlagRequired("answer-field")
+	c.baseCmd.AddCommand(c.prefCmd)
+
 	return c
 }
 
@@ -104,10 +365,12 @@ func (c *TuneCommand) list(cmd *cobra.Command, args []string) error {
 	limit, _ := cmd.Flags().GetInt("limit")
 	after, _ := cmd.Flags().GetString("after")
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	metadata, _ := cmd.Flags().GetStringToString("metadata")
+	filter := openai.FineTuneFilter{Metadata: metadata, Limit: limit, After: after}
 
 	// Retrieve the raw OpenAI response?
 	if c.raw {
-		body, e := c.apiClient.ListFineTunesRaw(ctx, limit, after)
+		body, e := c.apiClient.ListFineTunesRaw(ctx, filter)
 		if body != nil {
 			fmt.Print(string(body))
 		}
@@ -118,7 +381,7 @@ func (c *TuneCommand) list(cmd *cobra.Command, args []string) error {
 	}
 
 	// Retrieve the fine-tuned models.
-	tunes, hasMore, err := c.apiClient.ListFineTunes(ctx, limit, after)
+	tunes, hasMore, err := c.apiClient.ListFineTunes(ctx, filter)
 	if err != nil {
 		return err
 	}
@@ -222,6 +485,13 @@ func (c *TuneCommand) create(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	base := cmd.Flag("base").Value.String()
 	suffix := cmd.Flag("suffix").Value.String()
+	seed, _ := cmd.Flags().GetInt("seed")
+	metadata, _ := cmd.Flags().GetStringToString("metadata")
+	wandb, _ := cmd.Flags().GetString("wandb")
+	method, err := c.method(cmd)
+	if err != nil {
+		return err
+	}
 	trainingFileID := args[0]
 	validationFileID := ""
 	if len(args) > 1 {
@@ -232,9 +502,10 @@ func (c *TuneCommand) create(cmd *cobra.Command, args []string) error {
 	if !c.apiClient.ValidModel(ctx, base) {
 		return fmt.Errorf("invalid base model: %s", base)
 	}
+	warnModel(base, openai.EndpointFineTuning)
 
 	// Validate the training file ID.
-	_, err := c.apiClient.ReadFile(ctx, trainingFileID)
+	_, err = c.apiClient.ReadFile(ctx, trainingFileID)
 	if err != nil {
 		return fmt.Errorf("invalid training file ID %s: %w", trainingFileID, err)
 	}
@@ -253,6 +524,17 @@ func (c *TuneCommand) create(cmd *cobra.Command, args []string) error {
 		ValidationFileID: validationFileID,
 		Model:            base,
 		Suffix:           suffix,
+		Method:           &method,
+		Seed:             seed,
+		Metadata:         psy.LimitMetadata(metadata),
+	}
+	if len(metadata) == 0 {
+		req.Metadata = nil
+	}
+	if wandb != "" {
+		req.Integrations = []openai.FineTuneIntegration{
+			{Type: "wandb", WandB: openai.WandBIntegration{Project: wandb}},
+		}
 	}
 	if c.raw {
 		body, err := c.apiClient.CreateFineTuneRaw(ctx, req)
@@ -267,6 +549,7 @@ func (c *TuneCommand) create(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		registerJobs(psy.NewFineTuneJob(tune))
 		j, err := json.MarshalIndent(tune, "", "  ")
 		if err != nil {
 			return fmt.Errorf("error marshalling FineTune JSON: %w", err)
@@ -276,6 +559,40 @@ func (c *TuneCommand) create(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// method reads the fine-tuning method and its hyperparameters from the create command flags.
+func (c *TuneCommand) method(cmd *cobra.Command) (openai.FineTuneMethod, error) {
+	methodType, _ := cmd.Flags().GetString("method")
+	epochs, _ := cmd.Flags().GetString("epochs")
+	batchSize, _ := cmd.Flags().GetString("batch-size")
+	lrMultiplier, _ := cmd.Flags().GetString("lr-multiplier")
+	beta, _ := cmd.Flags().GetString("beta")
+	graderPath, _ := cmd.Flags().GetString("grader")
+	var hp openai.HyperParameters
+	var err error
+	if hp.EpochCount, err = openai.ParseAuto[int](epochs); err != nil {
+		return openai.FineTuneMethod{}, fmt.Errorf("epochs: %w", err)
+	}
+	if hp.BatchSize, err = openai.ParseAuto[int](batchSize); err != nil {
+		return openai.FineTuneMethod{}, fmt.Errorf("batch size: %w", err)
+	}
+	if hp.LearningRate, err = openai.ParseAuto[float64](lrMultiplier); err != nil {
+		return openai.FineTuneMethod{}, fmt.Errorf("learning rate multiplier: %w", err)
+	}
+	if hp.Beta, err = openai.ParseAuto[float64](beta); err != nil {
+		return openai.FineTuneMethod{}, fmt.Errorf("beta: %w", err)
+	}
+	var grader []byte
+	if graderPath != "" {
+		if grader, err = os.ReadFile(graderPath); err != nil {
+			return openai.FineTuneMethod{}, fmt.Errorf("read grader file %s: %w", graderPath, err)
+		}
+		if !json.Valid(grader) {
+			return openai.FineTuneMethod{}, fmt.Errorf("invalid grader file %s: not JSON", graderPath)
+		}
+	}
+	return openai.NewFineTuneMethod(strings.ToLower(methodType), hp, grader)
+}
+
 // cancel a fine-tuned model job in progress.
 func (c *TuneCommand) cancel(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
@@ -302,3 +619,1137 @@ func (c *TuneCommand) cancel(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// pause pauses running fine-tuning job(s).
+func (c *TuneCommand) pause(cmd *cobra.Command, args []string) error {
+	return c.update(args, c.apiClient.PauseFineTuneRaw, c.apiClient.PauseFineTune)
+}
+
+// resume resumes paused fine-tuning job(s).
+func (c *TuneCommand) resume(cmd *cobra.Command, args []string) error {
+	return c.update(args, c.apiClient.ResumeFineTuneRaw, c.apiClient.ResumeFineTune)
+}
+
+// update applies a job status change (e.g. pause) to each specified fine-tuning job,
+// printing the updated job (or the raw OpenAI response).
+func (c *TuneCommand) update(ids []string, raw func(context.Context, string) ([]byte, error),
+	typed func(context.Context, string) (openai.FineTuneJob, error)) error {
+	ctx := context.Background()
+	for _, id := range ids {
+		if c.raw {
+			body, err := raw(ctx, id)
+			if body != nil {
+				fmt.Print(string(body))
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		job, err := typed(ctx, id)
+		if err != nil {
+			return err
+		}
+		registerJobs(psy.NewFineTuneJob(job))
+		fmt.Printf("fine-tuning job %s %s: %s\n", job.ID, job.Status, job.Name())
+	}
+	return nil
+}
+
+// checkpoints lists the checkpoints of a fine-tuning job.
+func (c *TuneCommand) checkpoints(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	best, _ := cmd.Flags().GetBool("best")
+	limit, _ := cmd.Flags().GetInt("limit")
+	after, _ := cmd.Flags().GetString("after")
+	id := args[0]
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		body, e := c.apiClient.ListFineTuneCheckpointsRaw(ctx, id, limit, after)
+		if body != nil {
+			fmt.Print(string(body))
+		}
+		return e
+	}
+
+	// Retrieve the checkpoints (all of them, to select the best one):
+	var checkpoints []openai.FineTuneCheckpoint
+	var hasMore bool
+	var lastID string
+	for {
+		page, more, last, err := c.apiClient.ListFineTuneCheckpoints(ctx, id, limit, after)
+		if err != nil {
+			return err
+		}
+		checkpoints = append(checkpoints, page...)
+		hasMore, lastID = more && last != "", last
+		if !best || !hasMore {
+			break
+		}
+		after = lastID
+	}
+	if best {
+		checkpoint, ok := openai.BestCheckpoint(checkpoints)
+		if !ok {
+			return fmt.Errorf("no checkpoints found for fine-tuning job %s", id)
+		}
+		fmt.Println(checkpoint.FineTunedModelCheckpoint)
+		return nil
+	}
+
+	// Print the checkpoints.
+	if verbose {
+		j, err := json.MarshalIndent(checkpoints, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling Checkpoints JSON: %w", err)
+		}
+		fmt.Println(string(j))
+	} else {
+		fmt.Println("Step\tTrainLoss\tValidLoss\tValidAccuracy\tModel")
+		for _, k := range checkpoints {
+			fmt.Printf("%d\t%.4f\t%.4f\t%.4f\t%s\n", k.StepNumber, k.Metrics.TrainingLoss, k.ValidationLoss(),
+				cmp.Or(k.Metrics.FullValidationAccuracy, k.Metrics.ValidationAccuracy), k.FineTunedModelCheckpoint)
+		}
+	}
+	if hasMore {
+		fmt.Printf("More results available. Use --limit=%d --after=%s to retrieve.\n", limit, lastID)
+	}
+	return nil
+}
+
+// metrics shows the learning curves of one or more fine-tuning jobs.
+func (c *TuneCommand) metrics(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	output, _ := cmd.Flags().GetString("output")
+	steps, _ := cmd.Flags().GetBool("steps")
+	width, _ := cmd.Flags().GetInt("width")
+
+	// Retrieve the step metrics of each job:
+	jobs := make([]openai.FineTuneJob, 0, len(args))
+	curves := make(map[string][]openai.FineTuneMetric, len(args))
+	for _, id := range args {
+		job, err := c.apiClient.ReadFineTune(ctx, id)
+		if err != nil {
+			return err
+		}
+		curve, err := c.stepMetrics(ctx, job)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+		curves[job.ID] = curve
+	}
+
+	// Compare the jobs side by side:
+	fmt.Println("JobID\tStatus\tSteps\tTrainLoss\tValidLoss\tMinValidLoss\tMinValidStep\tGap\tOverfit\tModel")
+	for _, job := range jobs {
+		s := openai.SummarizeFineTune(curves[job.ID])
+		fmt.Printf("%s\t%s\t%d\t%.4f\t%.4f\t%.4f\t%d\t%.4f\t%t\t%s\n", job.ID, job.Status, s.Steps, s.TrainingLoss,
+			s.ValidationLoss, s.MinValidationLoss, s.MinValidationStep, s.Gap(), s.IsOverfit(), job.Name())
+	}
+
+	// Draw the learning curves, with a shared scale for each job's train and validation loss:
+	for _, job := range jobs {
+		curve := curves[job.ID]
+		if len(curve) == 0 {
+			continue
+		}
+		train := make([]float64, len(curve))
+		valid := make([]float64, len(curve))
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for i, m := range curve {
+			train[i], valid[i] = m.TrainingLoss, m.EvalLoss()
+			for _, v := range []float64{train[i], valid[i]} {
+				if v > 0 {
+					lo, hi = min(lo, v), max(hi, v)
+				}
+			}
+		}
+		fmt.Printf("\n%s: loss %.4f to %.4f over %d steps\n", job.ID, lo, hi, curve[len(curve)-1].Step)
+		fmt.Printf("train\t%s\n", sparkline(train, width, lo, hi))
+		fmt.Printf("valid\t%s\n", sparkline(valid, width, lo, hi))
+		if steps {
+			fmt.Println("Step\tTrainLoss\tTrainAccuracy\tValidLoss\tValidAccuracy")
+			for _, m := range curve {
+				fmt.Printf("%d\t%.4f\t%.4f\t%.4f\t%.4f\n", m.Step, m.TrainingLoss, m.TrainingAccuracy, m.EvalLoss(),
+					cmp.Or(m.FullValidationAccuracy, m.ValidationAccuracy))
+			}
+		}
+	}
+
+	// Export the step metrics for plotting:
+	if output == "" {
+		return nil
+	}
+	t := &psy.Table{FieldNames: []string{"job_id", "step", "train_loss", "train_accuracy", "valid_loss", "valid_accuracy"}}
+	for _, job := range jobs {
+		for _, m := range curves[job.ID] {
+			t.Records = append(t.Records, psy.Record{
+				"job_id":         job.ID,
+				"step":           strconv.Itoa(m.Step),
+				"train_loss":     formatMetric(m.TrainingLoss),
+				"train_accuracy": formatMetric(m.TrainingAccuracy),
+				"valid_loss":     formatMetric(m.EvalLoss()),
+				"valid_accuracy": formatMetric(cmp.Or(m.FullValidationAccuracy, m.ValidationAccuracy)),
+			})
+		}
+	}
+	if err := t.WriteCSV(output); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d step metrics to %s\n", len(t.Records), output)
+	return nil
+}
+
+// stepMetrics returns the step metrics of a fine-tuning job from its result file,
+// or from its metrics events if the job hasn't produced a result file yet.
+func (c *TuneCommand) stepMetrics(ctx context.Context, job openai.FineTuneJob) ([]openai.FineTuneMetric, error) {
+	if len(job.ResultFiles) > 0 {
+		data, err := c.apiClient.DownloadFile(ctx, job.ResultFiles[0])
+		if err != nil {
+			return nil, err
+		}
+		return openai.ParseFineTuneResults(data)
+	}
+	var metrics []openai.FineTuneMetric
+	after := ""
+	for {
+		events, hasMore, err := c.apiClient.ListFineTuneEvents(ctx, job.ID, 100, after)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			if e.EventType == "metrics" {
+				metrics = append(metrics, e.Metrics)
+			}
+		}
+		if !hasMore || len(events) == 0 {
+			break
+		}
+		after = events[len(events)-1].ID
+	}
+	slices.SortFunc(metrics, func(a, b openai.FineTuneMetric) int { return a.Step - b.Step })
+	return metrics, nil
+}
+
+// sparkline draws the values as a line of block characters scaled from lo to hi.
+// Values are averaged into at most width columns; zero (missing) values are skipped,
+// leaving a blank column if none remain.
+func sparkline(values []float64, width int, lo, hi float64) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	width = max(min(width, len(values)), 1)
+	var sb strings.Builder
+	for col := 0; col < width; col++ {
+		sum, n := 0.0, 0
+		for _, v := range values[col*len(values)/width : (col+1)*len(values)/width] {
+			if v > 0 {
+				sum += v
+				n++
+			}
+		}
+		if n == 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if hi > lo {
+			level = int(math.Round((sum/float64(n) - lo) / (hi - lo) * float64(len(blocks)-1)))
+		}
+		sb.WriteRune(blocks[max(min(level, len(blocks)-1), 0)])
+	}
+	return sb.String()
+}
+
+// formatMetric formats a metric value for export, leaving missing (zero) values blank.
+func formatMetric(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// maxMonitorErrors is the number of consecutive failed polls after which a
+// fine-tuning job monitor gives up.
+const maxMonitorErrors = 5
+
+// monitor follows a fine-tuning job until it's done, logging status changes and
+// new events, and then applies the completion actions if the job succeeded. It
+// fails if the job can't be read at first, or if polling fails with a client
+// error (e.g. an unknown job ID) or maxMonitorErrors times in a row.
+func (c *TuneCommand) monitor(cmd *cobra.Command, args []string) error {
+	wait, _ := cmd.Flags().GetInt("wait")
+	command, _ := cmd.Flags().GetString("exec")
+	envFile, _ := cmd.Flags().GetString("save-env")
+	envKey, _ := cmd.Flags().GetString("env-key")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	id := args[0]
+	status := ""
+	lastEventID := ""
+	failures := 0
+	fmt.Printf("monitoring fine-tuning job %s... (Ctrl+C to stop)\n", id)
+	for {
+		// Log status changes, and stop when the job is done:
+		job, err := c.apiClient.ReadFineTune(ctx, id)
+		if err == nil {
+			if job.Status != status {
+				fmt.Printf("%s\t%s\t%s -> %s\n", time.Now().Format(time.DateTime), id, cmp.Or(status, "new"), job.Status)
+				status = job.Status
+				registerJobs(psy.NewFineTuneJob(job))
+			}
+
+			// Log new events, oldest first:
+			var events []openai.FineTuneEvent
+			events, err = openai.FineTuneEventsSince(lastEventID, func(after string) ([]openai.FineTuneEvent, bool, error) {
+				return c.apiClient.ListFineTuneEvents(ctx, id, 100, after)
+			})
+			for _, e := range events {
+				fmt.Printf("%s\t%s\t%s\n", time.Unix(e.CreatedAt, 0).Format(time.DateTime), e.Level, e.Message)
+				lastEventID = e.ID
+			}
+			if job.IsDone() {
+				return c.complete(ctx, job, command, envFile, envKey)
+			}
+		}
+
+		// Retry failed polls, unless retrying won't help:
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("monitor fine-tuning job %s: interrupted", id)
+			}
+			failures++
+			if status == "" || failures >= maxMonitorErrors || openai.IsClientError(err) {
+				return fmt.Errorf("monitor fine-tuning job %s: %w", id, err)
+			}
+			fmt.Fprintln(os.Stderr, "warning:", err)
+		} else {
+			failures = 0
+		}
+		if err = sleep(ctx, time.Duration(max(wait, 1))*time.Second); err != nil {
+			return fmt.Errorf("monitor fine-tuning job %s: interrupted", id)
+		}
+	}
+}
+
+// complete applies the completion actions of a finished fine-tuning job: saving
+// the fine-tuned model ID to an env file, and running a shell command. It returns
+// an error if the job didn't succeed.
+func (c *TuneCommand) complete(ctx context.Context, job openai.FineTuneJob, command, envFile, envKey string) error {
+	if job.Status != "succeeded" {
+		if job.Error.Message != "" {
+			return fmt.Errorf("fine-tuning job %s %s: %s", job.ID, job.Status, job.Error.Message)
+		}
+		return fmt.Errorf("fine-tuning job %s %s", job.ID, job.Status)
+	}
+	fmt.Printf("fine-tuned model: %s\n", job.FineTunedModel)
+	if envFile != "" {
+		if err := psy.SaveEnv(envFile, envKey, job.FineTunedModel); err != nil {
+			return err
+		}
+		fmt.Printf("saved %s in %s\n", envKey, envFile)
+	}
+	if command != "" {
+		x := exec.CommandContext(ctx, "sh", "-c", command)
+		x.Env = append(os.Environ(), "FINE_TUNED_MODEL="+job.FineTunedModel, "FINE_TUNE_JOB_ID="+job.ID)
+		x.Stdin, x.Stdout, x.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := x.Run(); err != nil {
+			return fmt.Errorf("run command %q: %w", command, err)
+		}
+	}
+	return nil
+}
+
+// prepare generates training and validation files of example conversations from
+// a CSV file of answers with target completions.
+func (c *TuneCommand) prepare(cmd *cobra.Command, args []string) error {
+	completionField, _ := cmd.Flags().GetString("completion-field")
+	completionPath, _ := cmd.Flags().GetString("completion-template")
+	validation, _ := cmd.Flags().GetFloat64("validation")
+	stratify, _ := cmd.Flags().GetString("stratify")
+	seed, _ := cmd.Flags().GetInt64("seed")
+	output, _ := cmd.Flags().GetString("output")
+	p := psy.ChatParameters{AnswerFile: args[0]}
+	p.PromptFile, _ = cmd.Flags().GetString("prompt")
+	p.SystemFile, _ = cmd.Flags().GetString("system")
+	p.ConversationFile, _ = cmd.Flags().GetString("conversation")
+	p.QuestionID, _ = cmd.Flags().GetString("question-id")
+	p.QuestionField, _ = cmd.Flags().GetString("question-field")
+	p.AnswerField, _ = cmd.Flags().GetString("answer-field")
+	if len(args) > 1 {
+		p.QuestionFile = args[1]
+	}
+	if (completionField == "") == (completionPath == "") {
+		return fmt.Errorf("specify either a completion field or a completion template")
+	}
+	if validation < 0 || validation >= 1 {
+		return fmt.Errorf("invalid validation fraction %g: expecting 0 to 1", validation)
+	}
+	if !cmd.Flags().Changed("stratify") {
+		stratify = completionField
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if output == "" {
+		output = strings.TrimSuffix(p.AnswerFile, ".csv")
+	}
+
+	// Read the prompt templates and answers:
+	prompter, err := psy.NewPrompter(p)
+	if err != nil {
+		return err
+	}
+	var completionTemplate *psy.Template
+	if completionPath != "" {
+		if completionTemplate, err = psy.ReadTemplate(completionPath); err != nil {
+			return fmt.Errorf("completion template file: %w", err)
+		}
+	}
+	answers, err := psy.ReadCSVTable(p.AnswerFile)
+	if err != nil {
+		return fmt.Errorf("answer file: %w", err)
+	}
+	if err = prompter.Validate(answers, p.AnswerFile); err != nil {
+		return err
+	}
+	for _, field := range []string{completionField, stratify} {
+		if field != "" && !answers.HasField(field) {
+			return fmt.Errorf("field %s not found in %s", field, p.AnswerFile)
+		}
+	}
+
+	// Generate an example conversation for each answer with a completion:
+	var examples []tuneExample[openai.FineTuneRecord]
+	skipped := 0
+	for i, a := range answers.Records {
+		messages, ok, err := prompter.Messages(a)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+2, err)
+		}
+		completion := strings.TrimSpace(a[completionField])
+		if completionTemplate != nil {
+			if completion, err = completionTemplate.Execute(a); err != nil {
+				return fmt.Errorf("row %d: completion: %w", i+2, err)
+			}
+			completion = strings.TrimSpace(completion)
+		}
+		if !ok || completion == "" {
+			skipped++
+			continue
+		}
+		examples = append(examples, tuneExample[openai.FineTuneRecord]{stratum: a[stratify],
+			record: openai.NewFineTuneRecordMessages(messages, completion)})
+	}
+	if len(examples) == 0 {
+		return fmt.Errorf("no examples generated from %s", p.AnswerFile)
+	}
+	if skipped > 0 {
+		fmt.Printf("skipped %d answers without an answer or completion\n", skipped)
+	}
+
+	// Split the examples, and write the files:
+	paths, err := writeExamples(output, examples, validation, stratify, seed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("upload with: gpt file upload %s\n", paths[0])
+	return nil
+}
+
+// tuneExample is a fine-tuning example (e.g. a FineTuneRecord), with its stratum
+// (e.g. score) for splitting the examples into training and validation sets.
+type tuneExample[T any] struct {
+	stratum string
+	record  T
+}
+
+// writeExamples splits the examples into training and validation sets (stratified,
+// if a stratification field is named), and writes them to <output>-train.jsonl and
+// <output>-valid.jsonl. It returns the paths of the files written.
+func writeExamples[T any](output string, examples []tuneExample[T], validation float64, stratify string,
+	seed int64) ([]string, error) {
+	var byStratum func(tuneExample[T]) string
+	if stratify != "" {
+		byStratum = func(e tuneExample[T]) string { return e.stratum }
+	}
+	train, valid := psy.Split(examples, validation, byStratum, seed)
+	files := []struct {
+		path     string
+		examples []tuneExample[T]
+	}{{output + "-train.jsonl", train}, {output + "-valid.jsonl", valid}}
+	var paths []string
+	for _, f := range files {
+		if len(f.examples) == 0 {
+			continue
+		}
+		records := make([]T, len(f.examples))
+		for i, e := range f.examples {
+			records[i] = e.record
+		}
+		if err := psy.WriteJSONL(f.path, records); err != nil {
+			return paths, err
+		}
+		paths = append(paths, f.path)
+		fmt.Printf("wrote %d examples to %s\n", len(records), f.path)
+	}
+
+	// Summarize the split:
+	if stratify != "" {
+		counts := make(map[string][2]int)
+		for i, set := range [][]tuneExample[T]{train, valid} {
+			for _, e := range set {
+				n := counts[e.stratum]
+				n[i]++
+				counts[e.stratum] = n
+			}
+		}
+		fmt.Printf("%s\tTrain\tValid\n", stratify)
+		for _, k := range slices.Sorted(maps.Keys(counts)) {
+			fmt.Printf("%s\t%d\t%d\n", k, counts[k][0], counts[k][1])
+		}
+	}
+	fmt.Printf("split with seed %d\n", seed)
+	return paths, nil
+}
+
+// validate checks local fine-tuning data files, and estimates the training cost.
+func (c *TuneCommand) validate(cmd *cobra.Command, args []string) error {
+	base, _ := cmd.Flags().GetString("base")
+	epochFlag, _ := cmd.Flags().GetString("epochs")
+	epochs, err := openai.ParseAuto[int](epochFlag)
+	if err != nil {
+		return fmt.Errorf("invalid epochs %s: %w", epochFlag, err)
+	}
+	warnModel(base, openai.EndpointFineTuning)
+	info, _ := openai.Registry.Lookup(base)
+
+	// Validate each file:
+	var files []openai.FineTuneData
+	problems := 0
+	for _, path := range args {
+		data, e := os.ReadFile(path)
+		if e != nil {
+			return fmt.Errorf("read fine-tuning data file %s: %w", path, e)
+		}
+		d := openai.ValidateFineTuneData(data, info.FineTuningContext)
+		if len(files) == 0 && d.Examples < openai.MinFineTuneExamples {
+			d.Errors = append(d.Errors, openai.FineTuneDataError{
+				Message: fmt.Sprintf("too few examples: %d (minimum %d)", d.Examples, openai.MinFineTuneExamples)})
+		}
+		for _, p := range d.Errors {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, p.Error())
+		}
+		for _, p := range d.Warnings {
+			fmt.Fprintf(os.Stderr, "%s: warning: %s\n", path, p.Error())
+		}
+		problems += len(d.Errors)
+		files = append(files, d)
+		fmt.Printf("%s: %d examples, %d errors, %d warnings\n", path, d.Examples, len(d.Errors), len(d.Warnings))
+		if len(d.Tokens) > 0 {
+			tokens := slices.Sorted(slices.Values(d.Tokens))
+			fmt.Printf("  tokens per example: min %d, median %d, p90 %d, max %d (total %d)\n", tokens[0],
+				tokens[len(tokens)/2], tokens[len(tokens)*9/10], tokens[len(tokens)-1], d.TotalTokens())
+		}
+	}
+	if len(files) > 1 {
+		for _, p := range openai.FineTuneOverlap(files[0], files[1]) {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", args[1], p.Error())
+			problems++
+		}
+	}
+
+	// Estimate the training cost:
+	n := epochs.Value
+	if epochs.Auto || n == 0 {
+		n = openai.AutoEpochs(files[0].Examples)
+	}
+	billed := files[0].TotalTokens() * n
+	fmt.Printf("estimated training: %d tokens x %d epochs = %d tokens", files[0].TotalTokens(), n, billed)
+	if info.Pricing.Training > 0 {
+		fmt.Printf(", about $%.2f for %s at $%.2f per million tokens", float64(billed)*info.Pricing.Training/1e6,
+			base, info.Pricing.Training)
+	}
+	fmt.Println()
+	if problems > 0 {
+		return fmt.Errorf("invalid fine-tuning data: %d problems found", problems)
+	}
+	return nil
+}
+
+// evaluate scores holdout answers with a fine-tuned model and comparison models,
+// and reports each model's agreement with the human scores.
+func (c *TuneCommand) evaluate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	humanField, _ := cmd.Flags().GetString("human-field")
+	compare, _ := cmd.Flags().GetStringSlice("compare")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	output, _ := cmd.Flags().GetString("output")
+	scoreSelect, _ := cmd.Flags().GetString("score-select")
+	p := psy.ChatParameters{AnswerFile: args[1], ScoreSelect: psy.Selection(strings.ToLower(scoreSelect))}
+	p.PromptFile, _ = cmd.Flags().GetString("prompt")
+	p.SystemFile, _ = cmd.Flags().GetString("system")
+	p.ConversationFile, _ = cmd.Flags().GetString("conversation")
+	p.QuestionID, _ = cmd.Flags().GetString("question-id")
+	p.QuestionField, _ = cmd.Flags().GetString("question-field")
+	p.AnswerField, _ = cmd.Flags().GetString("answer-field")
+	p.Temperature, _ = cmd.Flags().GetFloat32("temperature")
+	p.MaxTokens, _ = cmd.Flags().GetInt("max-tokens")
+	if len(args) > 2 {
+		p.QuestionFile = args[2]
+	}
+	if p.ScoreSelect != psy.First && p.ScoreSelect != psy.Last {
+		return fmt.Errorf("invalid score selection (expect first or last): %s", p.ScoreSelect)
+	}
+	if output == "" {
+		output = strings.TrimSuffix(p.AnswerFile, ".csv") + "-eval.csv"
+	}
+
+	// Identify the fine-tuned model, and the comparison models:
+	model, base, err := c.tunedModel(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if len(compare) == 0 && base != "" {
+		compare = []string{base}
+	}
+	models := append([]string{model}, compare...)
+	for _, m := range models {
+		if !c.apiClient.ValidModel(ctx, m) {
+			return fmt.Errorf("model %s is not a recognized model ID", m)
+		}
+		warnModel(m, openai.EndpointChat)
+	}
+
+	// Read the prompt templates and the holdout answers with human scores:
+	prompter, err := psy.NewPrompter(p)
+	if err != nil {
+		return err
+	}
+	answers, err := psy.ReadCSVTable(p.AnswerFile)
+	if err != nil {
+		return fmt.Errorf("answer file: %w", err)
+	}
+	if err = prompter.Validate(answers, p.AnswerFile); err != nil {
+		return err
+	}
+	if !answers.HasField(humanField) {
+		return fmt.Errorf("human score field %s not found in %s", humanField, p.AnswerFile)
+	}
+	prompts := make(map[string][]openai.Message)
+	human := make(map[string]float64)
+	for i, a := range answers.Records {
+		messages, ok, err := prompter.Messages(a)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+2, err)
+		}
+		score, e := psy.ParseScore(strings.TrimSpace(a[humanField]))
+		if !ok || e != nil {
+			continue
+		}
+		id := strconv.Itoa(i + 1)
+		prompts[id], human[id] = messages, float64(score)
+	}
+	if len(prompts) == 0 {
+		return fmt.Errorf("no answers with human scores found in %s", p.AnswerFile)
+	}
+
+	// Score the answers with each model, and measure its agreement with the human scores:
+	fmt.Printf("evaluating %d models on %d scored answers\n", len(models), len(prompts))
+	agreements := make([]psy.Agreement, len(models))
+	for m, modelID := range models {
+		p.Model = modelID
+		chats := make([]psy.Chat, 0, len(prompts))
+		for _, id := range slices.Sorted(maps.Keys(prompts)) {
+			chats = append(chats, psy.NewChatMessages(id, prompts[id], p))
+		}
+		results := c.completeChats(ctx, modelID, chats, batchSize, p.ScoreSelect)
+		scoreField, completionField := "score_"+modelID, "completion_"+modelID
+		answers.AddField(scoreField)
+		answers.AddField(completionField)
+		var actual, predicted []float64
+		for i, a := range answers.Records {
+			chat, ok := results[strconv.Itoa(i+1)]
+			if !ok {
+				continue
+			}
+			a[completionField] = chat.ErrMsg
+			if chat.ErrMsg == "" {
+				a[completionField], _ = chat.Response.FirstMessageContent()
+			}
+			if len(chat.Scores) > 0 {
+				a[scoreField] = fmt.Sprintf("%g", chat.Scores[0])
+				actual = append(actual, human[chat.ID])
+				predicted = append(predicted, float64(chat.Scores[0]))
+			}
+		}
+		agreements[m] = psy.NewAgreement(actual, predicted)
+	}
+	if err = answers.WriteCSV(output); err != nil {
+		return err
+	}
+
+	// Report the agreement of each model with the human scores:
+	fmt.Println("Model\tN\tMAE\tRMSE\tPearson\tSpearman\tQWK\tExact")
+	for m, a := range agreements {
+		fmt.Printf("%s\t%d\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\n", models[m], a.N, a.MAE, a.RMSE,
+			a.Pearson, a.Spearman, a.QWK, a.Exact)
+	}
+	for m, a := range agreements {
+		fmt.Printf("\n%s confusion matrix (%s):\n%s", models[m], humanField, a.String())
+	}
+	fmt.Printf("\nwrote %d answers with completions and scores to %s\n", answers.RecordCount(), output)
+	return nil
+}
+
+// tunedModel identifies the fine-tuned model of a job (or a model ID), and its
+// base model, if known.
+func (c *TuneCommand) tunedModel(ctx context.Context, id string) (model, base string, err error) {
+	if !strings.HasPrefix(id, "ftjob-") {
+		if parts := strings.Split(id, ":"); len(parts) > 1 && parts[0] == "ft" {
+			base = parts[1]
+		}
+		return id, base, nil
+	}
+	job, err := c.apiClient.ReadFineTune(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	if job.FineTunedModel == "" {
+		return "", "", fmt.Errorf("fine-tuning job %s has no fine-tuned model (status %s)", id, job.Status)
+	}
+	return job.FineTunedModel, job.Model, nil
+}
+
+// completeChats completes the chats concurrently, in batches, reporting progress.
+// Failed requests are retried once.
+func (c *TuneCommand) completeChats(ctx context.Context, model string, chats []psy.Chat, batchSize int,
+	sel psy.Selection) map[string]psy.Chat {
+	results := make(map[string]psy.Chat, len(chats))
+	var retries []psy.Chat
+	for i, batch := range psy.Batch(chats, max(batchSize, 1)) {
+		for id, chat := range psy.CompleteChatBatch(ctx, c.apiClient, batch, sel) {
+			results[id] = chat
+			if chat.ErrMsg != "" {
+				retries = append(retries, chat)
+			}
+		}
+		fmt.Printf("%s: batch %d: %d of %d chats completed\n", model, i+1, len(results), len(chats))
+	}
+	if len(retries) > 0 {
+		fmt.Printf("%s: retrying %d failed requests\n", model, len(retries))
+		for id, chat := range psy.CompleteChatBatch(ctx, c.apiClient, retries, sel) {
+			results[id] = chat
+			if chat.ErrMsg != "" {
+				fmt.Fprintf(os.Stderr, "warning: %s: %s\n", id, chat.ErrMsg)
+			}
+		}
+	}
+	return results
+}
+
+// distill prepares fine-tuning data from the results of a chat run, and optionally
+// creates a fine-tuning job with it.
+func (c *TuneCommand) distill(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	runID, _ := cmd.Flags().GetString("run")
+	humanField, _ := cmd.Flags().GetString("human-field")
+	maxDiff, _ := cmd.Flags().GetFloat64("max-diff")
+	validation, _ := cmd.Flags().GetFloat64("validation")
+	seed, _ := cmd.Flags().GetInt64("seed")
+	output, _ := cmd.Flags().GetString("output")
+	create, _ := cmd.Flags().GetBool("create")
+	base, _ := cmd.Flags().GetString("base")
+	suffix, _ := cmd.Flags().GetString("suffix")
+	resultsPath := args[0]
+	if validation < 0 || validation >= 1 {
+		return fmt.Errorf("invalid validation fraction %g: expecting 0 to 1", validation)
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if output == "" {
+		output = strings.TrimSuffix(resultsPath, ".csv") + "-distill"
+	}
+
+	// Identify the chat parameters of the run, from the registry and the flags:
+	var p psy.ChatParameters
+	var run psy.Job
+	if r, err := openRegistry(); err != nil {
+		fmt.Fprintln(os.Stderr, "warning:", err)
+	} else if runID != "" {
+		var ok bool
+		if run, ok = r.RunJob(runID); !ok {
+			return fmt.Errorf("run %s parameters not found in registry %s", runID, r.Path)
+		}
+		p = *run.Parameters
+	} else if j, ok := r.OutputJob(resultsPath); ok {
+		run, p = j, *j.Parameters
+		fmt.Printf("using the registered chat parameters of %s (prompt %s)\n", resultsPath, j.Prompt())
+	}
+	if cmd.Flags().Changed("prompt") || cmd.Flags().Changed("conversation") {
+		p.PromptFile, p.SystemFile, p.ConversationFile = "", "", ""
+	}
+	for flag, value := range map[string]*string{"prompt": &p.PromptFile, "system": &p.SystemFile,
+		"conversation": &p.ConversationFile, "question-id": &p.QuestionID, "question-field": &p.QuestionField, "answer-field": &p.AnswerField,
+		"score-field": &p.ScoreField} {
+		if cmd.Flags().Changed(flag) {
+			*value, _ = cmd.Flags().GetString(flag)
+		}
+	}
+	if len(args) > 1 {
+		p.QuestionFile = args[1]
+	}
+	p.ScoreField = cmp.Or(p.ScoreField, "score")
+	if (p.PromptFile == "" && p.ConversationFile == "") || p.AnswerField == "" {
+		return fmt.Errorf("chat parameters of %s not found: specify the prompt file and answer field", resultsPath)
+	}
+
+	// The requests are reconstructed from the templates, so they must match those of the run,
+	// even if they're specified with flags:
+	if changes := run.ChangedTemplates(p); len(changes) > 0 {
+		return fmt.Errorf("template files changed since run %s: %s", cmp.Or(run.RunID, run.ID), strings.Join(changes, ", "))
+	}
+
+	// Read the prompt templates and results:
+	prompter, err := psy.NewPrompter(p)
+	if err != nil {
+		return err
+	}
+	if conv := prompter.Conversation; conv != nil && conv.Examples != nil && conv.Examples.Sample && conv.Examples.Seed == 0 {
+		fmt.Fprintf(os.Stderr, "warning: conversation %s samples few-shot examples without a seed, "+
+			"so they won't match the run's requests\n", p.ConversationFile)
+	}
+	results, err := psy.ReadCSVTable(resultsPath)
+	if err != nil {
+		return fmt.Errorf("results file: %w", err)
+	}
+	if err = prompter.Validate(results, resultsPath); err != nil {
+		return err
+	}
+	for _, field := range []string{"chatID", "completion", p.ScoreField, humanField} {
+		if field != "" && !results.HasField(field) {
+			return fmt.Errorf("field %s not found in %s", field, resultsPath)
+		}
+	}
+
+	// Reconstruct each request, with its completion, keeping rows with valid (and agreeing) scores.
+	// Rows without a chat ID weren't sent to the model:
+	var examples []tuneExample[openai.FineTuneRecord]
+	var invalid, disagree int
+	for i, row := range results.Records {
+		if row["chatID"] == "" {
+			invalid++
+			continue
+		}
+		messages, ok, err := prompter.Messages(row)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+2, err)
+		}
+		completion := strings.TrimSpace(row["completion"])
+		score, err := psy.ParseScore(strings.TrimSpace(row[p.ScoreField]))
+		if !ok || completion == "" || err != nil {
+			invalid++
+			continue
+		}
+		if humanField != "" {
+			human, e := psy.ParseScore(strings.TrimSpace(row[humanField]))
+			if e != nil || math.Abs(float64(score-human)) > maxDiff {
+				disagree++
+				continue
+			}
+		}
+		examples = append(examples, tuneExample[openai.FineTuneRecord]{stratum: strconv.Itoa(int(math.Round(float64(score)))),
+			record: openai.NewFineTuneRecordMessages(messages, completion)})
+	}
+	fmt.Printf("selected %d of %d results (%d without a chat ID or valid score, %d disagreeing with %s)\n",
+		len(examples), results.RecordCount(), invalid, disagree, cmp.Or(humanField, "human scores"))
+	if len(examples) == 0 {
+		return fmt.Errorf("no examples selected from %s", resultsPath)
+	}
+
+	// Split the examples, and write the files:
+	paths, err := writeExamples(output, examples, validation, p.ScoreField, seed)
+	if err != nil || !create {
+		return err
+	}
+
+	// Validate and upload the files, and create the fine-tuning job:
+	if !c.apiClient.ValidModel(ctx, base) {
+		return fmt.Errorf("invalid base model: %s", base)
+	}
+	warnModel(base, openai.EndpointFineTuning)
+	info, _ := openai.Registry.Lookup(base)
+	var fileIDs []string
+	for _, path := range paths {
+		data, e := os.ReadFile(path)
+		if e != nil {
+			return fmt.Errorf("read fine-tuning data file %s: %w", path, e)
+		}
+		if d := openai.ValidateFineTuneData(data, info.FineTuningContext); !d.IsValid() {
+			for _, p := range d.Errors {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", path, p.Error())
+			}
+			return fmt.Errorf("invalid fine-tuning data file %s: %d problems found", path, len(d.Errors))
+		}
+		file, e := c.apiClient.UploadFile(ctx, filepath.Base(path), "fine-tune", data)
+		if e != nil {
+			return fmt.Errorf("upload fine-tuning data file %s: %w", path, e)
+		}
+		fmt.Printf("uploaded %s file %s: %s\n", file.Purpose, file.ID, file.FileName)
+		fileIDs = append(fileIDs, file.ID)
+	}
+	method, err := openai.NewFineTuneMethod(openai.MethodSupervised, openai.HyperParameters{}, nil)
+	if err != nil {
+		return err
+	}
+	req := openai.FineTuneRequest{
+		TrainingFileID: fileIDs[0],
+		Model:          base,
+		Suffix:         suffix,
+		Method:         &method,
+		Metadata:       psy.LimitMetadata(map[string]string{"distilled_from": resultsPath, "prompt_file": cmp.Or(p.PromptFile, p.ConversationFile)}),
+	}
+	if len(fileIDs) > 1 {
+		req.ValidationFileID = fileIDs[1]
+	}
+	job, err := c.apiClient.CreateFineTune(ctx, req)
+	if err != nil {
+		return err
+	}
+	registerJobs(psy.NewFineTuneJob(job, paths...))
+	fmt.Printf("created fine-tuning job %s: %s\n", job.ID, job.Status)
+	fmt.Printf("gpt tune monitor %s\n", job.ID)
+	return nil
+}
+
+// prefer generates preference pairs for DPO fine-tuning from sampled completions,
+// picked by a judge prompt or by raters.
+func (c *TuneCommand) prefer(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	samples, _ := cmd.Flags().GetInt("samples")
+	judgePath, _ := cmd.Flags().GetString("judge")
+	judgeModel, _ := cmd.Flags().GetString("judge-model")
+	preferredField, _ := cmd.Flags().GetString("preferred-field")
+	rejectedField, _ := cmd.Flags().GetString("rejected-field")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	validation, _ := cmd.Flags().GetFloat64("validation")
+	seed, _ := cmd.Flags().GetInt64("seed")
+	output, _ := cmd.Flags().GetString("output")
+	p := psy.ChatParameters{AnswerFile: args[0]}
+	p.PromptFile, _ = cmd.Flags().GetString("prompt")
+	p.SystemFile, _ = cmd.Flags().GetString("system")
+	p.ConversationFile, _ = cmd.Flags().GetString("conversation")
+	p.QuestionID, _ = cmd.Flags().GetString("question-id")
+	p.QuestionField, _ = cmd.Flags().GetString("question-field")
+	p.AnswerField, _ = cmd.Flags().GetString("answer-field")
+	p.Model, _ = cmd.Flags().GetString("model")
+	p.Temperature, _ = cmd.Flags().GetFloat32("temperature")
+	p.MaxTokens, _ = cmd.Flags().GetInt("max-tokens")
+	if len(args) > 1 {
+		p.QuestionFile = args[1]
+	}
+	rated := preferredField != "" || rejectedField != ""
+	switch {
+	case rated && (preferredField == "" || rejectedField == ""):
+		return fmt.Errorf("specify both the preferred and rejected fields")
+	case rated && judgePath != "":
+		return fmt.Errorf("specify either the rater fields or a judge prompt")
+	case samples < 2:
+		return fmt.Errorf("invalid number of samples %d: expecting at least 2", samples)
+	case validation < 0 || validation >= 1:
+		return fmt.Errorf("invalid validation fraction %g: expecting 0 to 1", validation)
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if output == "" {
+		output = strings.TrimSuffix(p.AnswerFile, ".csv") + "-prefer"
+	}
+
+	// Read the prompt templates and answers, and generate the prompts:
+	prompter, err := psy.NewPrompter(p)
+	if err != nil {
+		return err
+	}
+	answers, err := psy.ReadCSVTable(p.AnswerFile)
+	if err != nil {
+		return fmt.Errorf("answer file: %w", err)
+	}
+	if err = prompter.Validate(answers, p.AnswerFile); err != nil {
+		return err
+	}
+	prompts := make(map[string][]openai.Message)
+	for i, a := range answers.Records {
+		messages, ok, err := prompter.Messages(a)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+2, err)
+		}
+		if ok {
+			prompts[strconv.Itoa(i+1)] = messages
+		}
+	}
+
+	// Sample completions for each answer (unless they've been rated already), and pick
+	// the preferred and non-preferred completions with the judge:
+	if !rated {
+		if !c.apiClient.ValidModel(ctx, p.Model) {
+			return fmt.Errorf("model %s is not a recognized model ID", p.Model)
+		}
+		warnModel(p.Model, openai.EndpointChat)
+		c.sample(ctx, answers, prompts, p, samples, batchSize)
+		if judgePath == "" {
+			path := output + "-samples.csv"
+			if err = answers.WriteCSV(path); err != nil {
+				return err
+			}
+			fmt.Printf("wrote %d answers with %d sampled completions each to %s\n", len(prompts), samples, path)
+			fmt.Println("Record the preferred and non-preferred completion numbers, and then run this command")
+			fmt.Printf("with %s as the answer file and --preferred-field and --rejected-field.\n", path)
+			return nil
+		}
+		preferredField, rejectedField = "preferred", "non_preferred"
+		if err = c.judge(ctx, answers, prompts, judgePath, cmp.Or(judgeModel, p.Model), samples, batchSize); err != nil {
+			return err
+		}
+		path := output + "-samples.csv"
+		if err = answers.WriteCSV(path); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %d answers with sampled completions and judgments to %s\n", len(prompts), path)
+	}
+	for _, field := range []string{preferredField, rejectedField} {
+		if !answers.HasField(field) {
+			return fmt.Errorf("field %s not found in %s", field, p.AnswerFile)
+		}
+	}
+
+	// Generate a validated preference pair for each answer with picks:
+	var examples []tuneExample[openai.PreferenceRecord]
+	skipped := 0
+	for i, a := range answers.Records {
+		messages, ok := prompts[strconv.Itoa(i+1)]
+		preferred := strings.TrimSpace(a["completion"+strings.TrimSpace(a[preferredField])])
+		rejected := strings.TrimSpace(a["completion"+strings.TrimSpace(a[rejectedField])])
+		record := openai.NewPreferenceRecordMessages(messages, preferred, rejected)
+		if !ok {
+			continue
+		}
+		if err = record.Validate(); err != nil {
+			skipped++
+			fmt.Fprintf(os.Stderr, "warning: row %d: %v\n", i+2, err)
+			continue
+		}
+		examples = append(examples, tuneExample[openai.PreferenceRecord]{record: record})
+	}
+	if skipped > 0 {
+		fmt.Printf("skipped %d answers without a valid preference pair\n", skipped)
+	}
+	if len(examples) == 0 {
+		return fmt.Errorf("no preference pairs generated from %s", p.AnswerFile)
+	}
+	paths, err := writeExamples(output, examples, validation, "", seed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("upload with: gpt file upload %s, then: gpt tune create <fileID> --method dpo\n", paths[0])
+	return nil
+}
+
+// sample adds the specified number of sampled completions for the prompt messages
+// of each answer to the answer records (completion1, completion2, ...). Completions
+// are requested with the n parameter, and with repeated requests for any shortfall.
+func (c *TuneCommand) sample(ctx context.Context, answers *psy.Table, prompts map[string][]openai.Message,
+	p psy.ChatParameters, n, batchSize int) {
+	completions := make(map[string][]string)
+	var pending []psy.Chat
+	for _, id := range slices.Sorted(maps.Keys(prompts)) {
+		chat := psy.NewChatMessages(id, prompts[id], p)
+		chat.Request.N = n
+		pending = append(pending, chat)
+	}
+	for round := 0; round < n && len(pending) > 0; round++ {
+		results := c.completeChats(ctx, p.Model, pending, batchSize, psy.None)
+		var more []psy.Chat
+		for _, chat := range pending {
+			r := results[chat.ID]
+			for _, choice := range r.Response.Choices {
+				if text := strings.TrimSpace(choice.Message.Content); text != "" && len(completions[chat.ID]) < n {
+					completions[chat.ID] = append(completions[chat.ID], text)
+				}
+			}
+			if r.ErrMsg == "" && len(completions[chat.ID]) < n {
+				chat.Request.N = n - len(completions[chat.ID])
+				more = append(more, chat)
+			}
+		}
+		pending = more
+	}
+	for k := 1; k <= n; k++ {
+		answers.AddField("completion" + strconv.Itoa(k))
+	}
+	for i, a := range answers.Records {
+		for k, text := range completions[strconv.Itoa(i+1)] {
+			a["completion"+strconv.Itoa(k+1)] = text
+		}
+	}
+}
+
+// judge picks the preferred and non-preferred completions of each answer with a
+// judge prompt, recording their numbers in the "preferred" and "non_preferred"
+// fields. The last line of the judge's reply should give the best response number,
+// then the worst, separated by a comma (see psy.ParsePreference). The judge prompt
+// includes the final (user) message of each answer's prompt.
+func (c *TuneCommand) judge(ctx context.Context, answers *psy.Table, prompts map[string][]openai.Message, judgePath,
+	model string, n, batchSize int) error {
+	template, err := psy.ReadTemplate(judgePath)
+	if err != nil {
+		return fmt.Errorf("judge file: %w", err)
+	}
+	if !c.apiClient.ValidModel(ctx, model) {
+		return fmt.Errorf("model %s is not a recognized model ID", model)
+	}
+	var chats []psy.Chat
+	for i, a := range answers.Records {
+		id := strconv.Itoa(i + 1)
+		messages, ok := prompts[id]
+		if !ok {
+			continue
+		}
+		var responses strings.Builder
+		for k := 1; k <= n; k++ {
+			if text := a["completion"+strconv.Itoa(k)]; text != "" {
+				fmt.Fprintf(&responses, "Response %d:\n%s\n\n", k, text)
+			}
+		}
+		fields := maps.Clone(a)
+		fields["prompt"], fields["responses"] = messages[len(messages)-1].Content, strings.TrimSpace(responses.String())
+		judgePrompt, err := template.Execute(fields)
+		if err != nil {
+			return fmt.Errorf("row %d: judge: %w", i+2, err)
+		}
+		chats = append(chats, psy.NewChat(id, "", judgePrompt, psy.ChatParameters{Model: model}))
+	}
+	results := c.completeChats(ctx, model, chats, batchSize, psy.None)
+	answers.AddField("preferred")
+	answers.AddField("non_preferred")
+	for i, a := range answers.Records {
+		result, ok := results[strconv.Itoa(i+1)]
+		if !ok || result.ErrMsg != "" {
+			continue
+		}
+		reply, err := result.Response.FirstMessageContent()
+		if err != nil {
+			continue
+		}
+		best, worst, err := psy.ParsePreference(reply, n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: row %d: judge: %v\n", i+2, err)
+			continue
+		}
+		a["preferred"], a["non_preferred"] = strconv.Itoa(best), strconv.Itoa(worst)
+	}
+	return nil
+}
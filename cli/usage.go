@@ -0,0 +1,374 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gpt/openai"
+	"gpt/psy"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// UsageCommand is the command for reporting organization usage and costs.
+type UsageCommand struct {
+	apiClient      *openai.Client
+	rootCmd        *cobra.Command
+	baseCmd        *cobra.Command
+	completionsCmd *cobra.Command
+	embeddingsCmd  *cobra.Command
+	batchCmd       *cobra.Command
+	costsCmd       *cobra.Command
+	start          string
+	end            string
+	groupBy        []string
+	bucket         string
+	perBucket      bool
+	project        []string
+	outputPath     string
+	raw            bool
+}
+
+// NewUsageCommand creates and initializes the usage commands.
+func NewUsageCommand(apiClient *openai.Client, root *cobra.Command) *UsageCommand {
+	// Base Command
+	c := &UsageCommand{
+		apiClient: apiClient,
+		rootCmd:   root,
+	}
+	c.baseCmd = &cobra.Command{
+		Use:   "usage",
+		Short: "Report organization usage and costs",
+		Long: "Report organization usage and costs for a date range, grouped by model or project.\n" +
+			"These reports require an admin API key, provided with OPENAI_ADMIN_KEY.",
+	}
+	c.baseCmd.PersistentFlags().StringVarP(&c.start, "start", "s", "", "Start date YYYY-MM-DD (default: first day of this month)")
+	c.baseCmd.PersistentFlags().StringVarP(&c.end, "end", "e", "", "End date YYYY-MM-DD, exclusive (default: now)")
+	c.baseCmd.PersistentFlags().StringSliceVarP(&c.groupBy, "group-by", "g", nil, "Group by: model | project")
+	c.baseCmd.PersistentFlags().StringVarP(&c.bucket, "bucket", "b", "1d", "Bucket width: 1m | 1h | 1d")
+	c.baseCmd.PersistentFlags().BoolVarP(&c.perBucket, "per-bucket", "B", false, "Report each time bucket separately?")
+	c.baseCmd.PersistentFlags().StringSliceVarP(&c.project, "project", "p", nil, "Project ID filter (optional)")
+	c.baseCmd.PersistentFlags().StringVarP(&c.outputPath, "output", "o", "", "Output CSV file path (optional)")
+	c.baseCmd.PersistentFlags().BoolVarP(&c.raw, "raw", "r", false, "Raw OpenAI Response?")
+	c.rootCmd.AddCommand(c.baseCmd)
+
+	// Completions Command
+	// Example: gpt usage completions --start 2025-09-01 --end 2025-10-01 -g project,model -o usage.csv
+	c.completionsCmd = &cobra.Command{
+		Use:   "completions",
+		Short: "Report chat completions usage",
+		Long:  "Report chat completions token usage and request counts, including batch jobs.",
+		Args:  cobra.NoArgs,
+		RunE:  c.usage,
+	}
+	c.baseCmd.AddCommand(c.completionsCmd)
+
+	// Embeddings Command
+	c.embeddingsCmd = &cobra.Command{
+		Use:   "embeddings",
+		Short: "Report embeddings usage",
+		Long:  "Report embeddings token usage and request counts.",
+		Args:  cobra.NoArgs,
+		RunE:  c.usage,
+	}
+	c.baseCmd.AddCommand(c.embeddingsCmd)
+
+	// Batch Command
+	c.batchCmd = &cobra.Command{
+		Use:   "batch",
+		Short: "Report batch usage",
+		Long:  "Report chat completions token usage and request counts from batch jobs only.",
+		Args:  cobra.NoArgs,
+		RunE:  c.usage,
+	}
+	c.baseCmd.AddCommand(c.batchCmd)
+
+	// Costs Command
+	// Example: gpt usage costs --start 2025-09-01 --end 2025-10-01 -g project,model
+	c.costsCmd = &cobra.Command{
+		Use:   "costs",
+		Short: "Report organization costs",
+		Long: "Report organization costs in US dollars, in daily buckets. Costs grouped by model\n" +
+			"are reported by line item (e.g. \"gpt-4o-mini, input\").",
+		Args: cobra.NoArgs,
+		RunE: c.costs,
+	}
+	c.baseCmd.AddCommand(c.costsCmd)
+
+	return c
+}
+
+// usage is the handler for the "usage completions", "usage embeddings", and "usage batch" commands.
+func (c *UsageCommand) usage(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	request, err := c.request(false)
+	if err != nil {
+		return err
+	}
+	usageType := cmd.Name()
+	if usageType == "batch" {
+		batch := true
+		request.Batch = &batch
+		usageType = "completions"
+	}
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		return c.printRaw(func(r openai.UsageRequest) ([]byte, error) {
+			return c.apiClient.ListUsageRaw(ctx, usageType, r)
+		}, request)
+	}
+
+	// Retrieve all the time buckets
+	buckets, err := c.collect(func(r openai.UsageRequest) ([]openai.UsageBucket, bool, string, error) {
+		return c.apiClient.ListUsage(ctx, usageType, r)
+	}, request)
+	if err != nil {
+		return err
+	}
+
+	// Summarize the results
+	t := c.summarize(ctx, buckets, request.GroupBy, []string{"requests", "input_tokens", "cached_tokens", "output_tokens"},
+		func(_ string, rec psy.Record, r openai.UsageResult) {
+			addInt(rec, "requests", r.NumModelRequests)
+			addInt(rec, "input_tokens", r.InputTokens)
+			addInt(rec, "cached_tokens", r.InputCachedTokens)
+			addInt(rec, "output_tokens", r.OutputTokens)
+		})
+	return c.output(t)
+}
+
+// costs is the handler for the "usage costs" command.
+func (c *UsageCommand) costs(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	request, err := c.request(true)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		return c.printRaw(func(r openai.UsageRequest) ([]byte, error) {
+			return c.apiClient.ListCostsRaw(ctx, r)
+		}, request)
+	}
+
+	// Retrieve all the time buckets
+	buckets, err := c.collect(func(r openai.UsageRequest) ([]openai.UsageBucket, bool, string, error) {
+		return c.apiClient.ListCosts(ctx, r)
+	}, request)
+	if err != nil {
+		return err
+	}
+
+	// Summarize the results
+	totals := make(map[string]float64)
+	t := c.summarize(ctx, buckets, request.GroupBy, []string{"cost_usd"},
+		func(key string, rec psy.Record, r openai.UsageResult) {
+			if r.Amount != nil {
+				totals[key] += r.Amount.Value
+				rec["cost_usd"] = strconv.FormatFloat(totals[key], 'f', 4, 64)
+			}
+		})
+	return c.output(t)
+}
+
+// request builds a UsageRequest from the command line flags.
+func (c *UsageCommand) request(costs bool) (openai.UsageRequest, error) {
+	var r openai.UsageRequest
+	now := time.Now().UTC()
+	r.StartTime = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if c.start != "" {
+		t, err := time.Parse(time.DateOnly, c.start)
+		if err != nil {
+			return r, fmt.Errorf("invalid start date %s: %w", c.start, err)
+		}
+		r.StartTime = t
+	}
+	if c.end != "" {
+		t, err := time.Parse(time.DateOnly, c.end)
+		if err != nil {
+			return r, fmt.Errorf("invalid end date %s: %w", c.end, err)
+		}
+		if !t.After(r.StartTime) {
+			return r, fmt.Errorf("invalid end date %s: must be after the start date", c.end)
+		}
+		r.EndTime = t
+	}
+	r.BucketWidth = c.bucket
+	if costs {
+		if c.bucket != "1d" {
+			return r, fmt.Errorf("invalid bucket width %s: costs are reported in daily buckets (1d)", c.bucket)
+		}
+	} else if !slices.Contains([]string{"1m", "1h", "1d"}, c.bucket) {
+		return r, fmt.Errorf("invalid bucket width %s: expecting 1m, 1h, or 1d", c.bucket)
+	}
+	for _, g := range c.groupBy {
+		switch strings.ToLower(strings.TrimSpace(g)) {
+		case "project", "project_id":
+			r.GroupBy = append(r.GroupBy, "project_id")
+		case "model", "line_item":
+			if costs {
+				r.GroupBy = append(r.GroupBy, "line_item")
+			} else {
+				r.GroupBy = append(r.GroupBy, "model")
+			}
+		default:
+			return r, fmt.Errorf("invalid group-by %s: expecting model or project", g)
+		}
+	}
+	r.ProjectIDs = c.project
+	return r, nil
+}
+
+// printRaw prints the raw JSON response for each page of results.
+func (c *UsageCommand) printRaw(list func(openai.UsageRequest) ([]byte, error), r openai.UsageRequest) error {
+	for {
+		body, err := list(r)
+		if body != nil {
+			fmt.Println(string(body))
+		}
+		if err != nil {
+			return err
+		}
+		var page openai.UsagePage
+		if e := json.Unmarshal(body, &page); e != nil || !page.HasMore || page.NextPage == "" {
+			return e
+		}
+		r.Page = page.NextPage
+	}
+}
+
+// collect retrieves all the time buckets, following the pagination cursor.
+func (c *UsageCommand) collect(list func(openai.UsageRequest) ([]openai.UsageBucket, bool, string, error),
+	r openai.UsageRequest) ([]openai.UsageBucket, error) {
+	var buckets []openai.UsageBucket
+	for {
+		data, hasMore, nextPage, err := list(r)
+		if err != nil {
+			return buckets, err
+		}
+		buckets = append(buckets, data...)
+		if !hasMore || nextPage == "" {
+			return buckets, nil
+		}
+		r.Page = nextPage
+	}
+}
+
+// summarize aggregates the results of the time buckets into a Table, with one
+// record per group (and per bucket, if requested). The add function accumulates
+// each result into the record of its group, identified by key.
+func (c *UsageCommand) summarize(ctx context.Context, buckets []openai.UsageBucket, groupBy, valueFields []string,
+	add func(key string, rec psy.Record, r openai.UsageResult)) *psy.Table {
+	t := &psy.Table{}
+	if c.perBucket {
+		t.AddField("date")
+	}
+	var projects map[string]string
+	for _, g := range groupBy {
+		t.AddField(g)
+		if g == "project_id" {
+			t.AddField("project_name")
+			projects = c.projectNames(ctx)
+		}
+	}
+	for _, f := range valueFields {
+		t.AddField(f)
+	}
+
+	// Aggregate the results by group
+	index := make(map[string]psy.Record)
+	for _, b := range buckets {
+		date := b.Start().Format(time.DateTime)
+		if c.bucket == "1d" {
+			date = b.Start().Format(time.DateOnly)
+		}
+		for _, r := range b.Results {
+			key := r.Group(groupBy...)
+			if c.perBucket {
+				key = date + " " + key
+			}
+			rec, ok := index[key]
+			if !ok {
+				rec = psy.Record{"date": date}
+				for _, g := range groupBy {
+					rec[g] = r.Group(g)
+				}
+				if projects != nil {
+					rec["project_name"] = projects[r.ProjectID]
+				}
+				index[key] = rec
+				t.Records = append(t.Records, rec)
+			}
+			add(key, rec, r)
+		}
+	}
+
+	// Sort the records by date and group
+	slices.SortStableFunc(t.Records, func(a, b psy.Record) int {
+		for _, f := range t.FieldNames[:len(t.FieldNames)-len(valueFields)] {
+			if n := strings.Compare(a[f], b[f]); n != 0 {
+				return n
+			}
+		}
+		return 0
+	})
+	return t
+}
+
+// projectNames maps project IDs to names. Errors are reported as warnings, because
+// the project names are informational.
+func (c *UsageCommand) projectNames(ctx context.Context) map[string]string {
+	names := make(map[string]string)
+	after := ""
+	for {
+		projects, hasMore, lastID, err := c.apiClient.ListProjects(ctx, 100, after)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "warning:", err)
+			return names
+		}
+		for _, p := range projects {
+			names[p.ID] = p.Name
+		}
+		if !hasMore || lastID == "" {
+			return names
+		}
+		after = lastID
+	}
+}
+
+// output writes the Table to the output CSV file, if specified, or prints it to the console.
+func (c *UsageCommand) output(t *psy.Table) error {
+	if c.outputPath != "" {
+		if err := t.WriteCSV(c.outputPath); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d records to %s\n", t.RecordCount(), c.outputPath)
+		return nil
+	}
+	if t.RecordCount() == 0 {
+		fmt.Println("No usage found.")
+		return nil
+	}
+	fmt.Println(strings.Join(t.FieldNames, "\t"))
+	for _, rec := range t.Records {
+		var row []string
+		for _, f := range t.FieldNames {
+			row = append(row, rec[f])
+		}
+		fmt.Println(strings.Join(row, "\t"))
+	}
+	return nil
+}
+
+// addInt adds an integer value to the specified field of a Record.
+func addInt(rec psy.Record, field string, value int64) {
+	v, _ := strconv.ParseInt(rec[field], 10, 64)
+	rec[field] = strconv.FormatInt(v+value, 10)
+}
@@ -0,0 +1,549 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gpt/openai"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// VectorCommand is the command for managing vector stores, used for file search.
+type VectorCommand struct {
+	apiClient *openai.Client
+	rootCmd   *cobra.Command
+	baseCmd   *cobra.Command
+	createCmd *cobra.Command
+	listCmd   *cobra.Command
+	readCmd   *cobra.Command
+	deleteCmd *cobra.Command
+	filesCmd  *cobra.Command
+	addCmd    *cobra.Command
+	removeCmd *cobra.Command
+	batchCmd  *cobra.Command
+	searchCmd *cobra.Command
+	raw       bool
+}
+
+// NewVectorCommand creates and initializes the vector store commands.
+func NewVectorCommand(apiClient *openai.Client, root *cobra.Command) *VectorCommand {
+	// Base Command
+	c := &VectorCommand{
+		apiClient: apiClient,
+		rootCmd:   root,
+	}
+	c.baseCmd = &cobra.Command{
+		Use:   "vector",
+		Short: "Manage vector stores",
+		Long:  "Manage vector stores, used to search uploaded files (e.g. codebooks and protocols).",
+	}
+	c.baseCmd.PersistentFlags().BoolVarP(&c.raw, "raw", "r", false, "Raw OpenAI Response?")
+	c.rootCmd.AddCommand(c.baseCmd)
+
+	// Create Command
+	// Example: gpt vector create codebooks file-abc123 file-def456 --expires-days 30 -w 5
+	c.createCmd = &cobra.Command{
+		Use:   "create <name> [fileID]...",
+		Short: "Create a vector store",
+		Long:  "Create a vector store with the specified name, optionally including uploaded files.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.create,
+	}
+	c.createCmd.Flags().IntP("expires-days", "x", 0, "Expire after days of inactivity (optional)")
+	c.createCmd.Flags().Int("chunk-size", 0, "Static chunk size in tokens (optional, 100-4096)")
+	c.createCmd.Flags().Int("chunk-overlap", 0, "Static chunk overlap in tokens (optional)")
+	c.createCmd.Flags().StringToStringP("metadata", "M", nil, "Metadata key=value pairs (optional)")
+	c.createCmd.Flags().IntP("wait", "w", 0, "Wait for file processing? Polling interval in seconds")
+	c.baseCmd.AddCommand(c.createCmd)
+
+	// List Command
+	c.listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List vector stores",
+		Long:  "List metadata of available vector stores.",
+		RunE:  c.list,
+	}
+	c.listCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
+	c.listCmd.Flags().IntP("limit", "l", 20, "Limit")
+	c.listCmd.Flags().StringP("after", "a", "", "After (last ID received)")
+	c.baseCmd.AddCommand(c.listCmd)
+
+	// Read Command
+	c.readCmd = &cobra.Command{
+		Use:   "read <storeID> [storeID]...",
+		Short: "Read specified vector store(s)",
+		Long:  "Read the metadata about one or more vector stores, specified by ID.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.read,
+	}
+	c.baseCmd.AddCommand(c.readCmd)
+
+	// Delete Command
+	c.deleteCmd = &cobra.Command{
+		Use:   "delete <storeID> [storeID]...",
+		Short: "Delete specified vector store(s)",
+		Long:  "Delete one or more vector stores, specified by ID. The files themselves are not deleted.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  c.delete,
+	}
+	c.baseCmd.AddCommand(c.deleteCmd)
+
+	// Files Command
+	c.filesCmd = &cobra.Command{
+		Use:   "files <storeID>",
+		Short: "List the files in a vector store",
+		Long:  "List the files attached to a vector store, with their processing status.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  c.files,
+	}
+	c.filesCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
+	c.filesCmd.Flags().StringP("status", "s", "", "Status filter: in_progress | completed | failed | cancelled")
+	c.filesCmd.Flags().IntP("limit", "l", 20, "Limit")
+	c.filesCmd.Flags().StringP("after", "a", "", "After (last ID received)")
+	c.baseCmd.AddCommand(c.filesCmd)
+
+	// Add Command
+	// Example: gpt vector add vs_abc123 file-abc123 file-def456 --attribute kind=codebook -w 5
+	c.addCmd = &cobra.Command{
+		Use:   "add <storeID> <fileID> [fileID]...",
+		Short: "Add files to a vector store",
+		Long:  "Add one or more uploaded files to a vector store as a file batch.",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  c.add,
+	}
+	c.addCmd.Flags().StringToStringP("attribute", "A", nil, "File attribute key=value pairs (optional)")
+	c.addCmd.Flags().IntP("wait", "w", 0, "Wait for file processing? Polling interval in seconds")
+	c.baseCmd.AddCommand(c.addCmd)
+
+	// Remove Command
+	c.removeCmd = &cobra.Command{
+		Use:   "remove <storeID> <fileID> [fileID]...",
+		Short: "Remove files from a vector store",
+		Long:  "Remove one or more files from a vector store. The files themselves are not deleted.",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  c.remove,
+	}
+	c.baseCmd.AddCommand(c.removeCmd)
+
+	// Batch Command
+	c.batchCmd = &cobra.Command{
+		Use:   "batch <storeID> <batchID>",
+		Short: "Read or monitor a vector store file batch",
+		Long:  "Read the status of a vector store file batch, optionally waiting for it to finish processing.",
+		Args:  cobra.ExactArgs(2),
+		RunE:  c.batch,
+	}
+	c.batchCmd.Flags().IntP("wait", "w", 0, "Wait for file processing? Polling interval in seconds")
+	c.batchCmd.Flags().Bool("cancel", false, "Cancel the file batch?")
+	c.baseCmd.AddCommand(c.batchCmd)
+
+	// Search Command
+	// Example: gpt vector search vs_abc123 "How do we score purpose statements?" -n 5
+	c.searchCmd = &cobra.Command{
+		Use:   "search <storeID> <query>",
+		Short: "Search a vector store",
+		Long:  "Search a vector store for the content chunks most relevant to a query.",
+		Args:  cobra.ExactArgs(2),
+		RunE:  c.search,
+	}
+	c.searchCmd.Flags().BoolP("verbose", "v", false, "Verbose? (full JSON)")
+	c.searchCmd.Flags().IntP("max-results", "n", 10, "Maximum number of results (1-50)")
+	c.searchCmd.Flags().Float64("score-threshold", 0, "Minimum result score (0-1)")
+	c.searchCmd.Flags().Bool("rewrite", false, "Rewrite the query for vector search?")
+	c.baseCmd.AddCommand(c.searchCmd)
+
+	return c
+}
+
+// create is the handler for the "vector create" command.
+func (c *VectorCommand) create(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	expiresDays, _ := cmd.Flags().GetInt("expires-days")
+	chunkSize, _ := cmd.Flags().GetInt("chunk-size")
+	chunkOverlap, _ := cmd.Flags().GetInt("chunk-overlap")
+	metadata, _ := cmd.Flags().GetStringToString("metadata")
+	wait, _ := cmd.Flags().GetInt("wait")
+
+	// Identify the request parameters
+	request := openai.VectorStoreRequest{
+		Name:     args[0],
+		FileIDs:  args[1:],
+		Metadata: metadata,
+	}
+	if expiresDays > 0 {
+		request.ExpiresAfter = &openai.ExpiresAfter{Anchor: "last_active_at", Days: expiresDays}
+	}
+	if chunkSize > 0 {
+		request.ChunkingStrategy = &openai.ChunkingStrategy{
+			Type: "static",
+			Static: &openai.StaticChunking{
+				MaxChunkSizeTokens: chunkSize,
+				ChunkOverlapTokens: chunkOverlap,
+			},
+		}
+	}
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		// Echo the Request
+		j, e := json.MarshalIndent(request, "", "  ")
+		if e != nil {
+			return fmt.Errorf("error marshalling JSON request: %w", e)
+		}
+		fmt.Println(string(j))
+		// Output the Response
+		b, e := c.apiClient.CreateVectorStoreRaw(ctx, request)
+		if len(b) > 0 {
+			fmt.Print(string(b))
+		}
+		return e
+	}
+
+	// Create the vector store
+	store, err := c.apiClient.CreateVectorStore(ctx, request)
+	if err != nil {
+		return err
+	}
+	if wait > 0 && len(request.FileIDs) > 0 {
+		fmt.Println("polling for file processing... (Ctrl+C to cancel)")
+		for store.Status == "in_progress" {
+			fmt.Printf("%s %s, %s\n", store.ID, store.Status, store.FileCounts)
+			time.Sleep(time.Duration(wait) * time.Second)
+			store, err = c.apiClient.ReadVectorStore(ctx, store.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	j, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling VectorStore JSON: %w", err)
+	}
+	fmt.Println(string(j))
+	return nil
+}
+
+// list is the handler for the "vector list" command.
+func (c *VectorCommand) list(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	limit, _ := cmd.Flags().GetInt("limit")
+	after, _ := cmd.Flags().GetString("after")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		body, e := c.apiClient.ListVectorStoresRaw(ctx, limit, after)
+		if body != nil {
+			fmt.Println(string(body))
+		}
+		return e
+	}
+
+	// Retrieve the vector stores
+	stores, hasMore, lastID, err := c.apiClient.ListVectorStores(ctx, limit, after)
+	if err != nil {
+		return err
+	}
+	if len(stores) == 0 {
+		fmt.Println("No vector stores found.")
+		return nil
+	}
+
+	// Print the vector stores
+	if verbose {
+		j, err := json.MarshalIndent(stores, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling VectorStores JSON: %w", err)
+		}
+		fmt.Println(string(j))
+	} else {
+		fmt.Println("StoreID\tName\tCreatedAt\tStatus\tFiles\tBytes")
+		for _, s := range stores {
+			fmt.Println(s.String())
+		}
+	}
+	if hasMore {
+		fmt.Printf("More results available. Use --limit=%d --after=%s to retrieve.\n", limit, lastID)
+	}
+	return nil
+}
+
+// read is the handler for the "vector read" command.
+func (c *VectorCommand) read(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	for _, storeID := range args {
+		// Retrieve the raw OpenAI response?
+		if c.raw {
+			body, e := c.apiClient.ReadVectorStoreRaw(ctx, storeID)
+			if body != nil {
+				fmt.Print(string(body))
+			}
+			if e != nil {
+				return e
+			}
+			continue
+		}
+		store, e := c.apiClient.ReadVectorStore(ctx, storeID)
+		if e != nil {
+			return e
+		}
+		j, err := json.MarshalIndent(store, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling VectorStore JSON: %w", err)
+		}
+		fmt.Println(string(j))
+	}
+	return nil
+}
+
+// delete is the handler for the "vector delete" command.
+func (c *VectorCommand) delete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	for _, storeID := range args {
+		body, err := c.apiClient.DeleteVectorStoreRaw(ctx, storeID)
+		if err != nil {
+			return err
+		}
+		if body != nil && c.raw {
+			fmt.Print(string(body))
+		} else {
+			fmt.Println("Deleted:", storeID)
+		}
+	}
+	return nil
+}
+
+// files is the handler for the "vector files" command.
+func (c *VectorCommand) files(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	storeID := args[0]
+	status, _ := cmd.Flags().GetString("status")
+	limit, _ := cmd.Flags().GetInt("limit")
+	after, _ := cmd.Flags().GetString("after")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		body, e := c.apiClient.ListVectorStoreFilesRaw(ctx, storeID, status, limit, after)
+		if body != nil {
+			fmt.Println(string(body))
+		}
+		return e
+	}
+
+	// Retrieve the vector store files
+	files, hasMore, lastID, err := c.apiClient.ListVectorStoreFiles(ctx, storeID, status, limit, after)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("No vector store files found.")
+		return nil
+	}
+
+	// Print the vector store files
+	if verbose {
+		j, err := json.MarshalIndent(files, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling VectorStoreFiles JSON: %w", err)
+		}
+		fmt.Println(string(j))
+	} else {
+		fmt.Println("FileID\tCreatedAt\tStatus\tBytes\tError")
+		for _, f := range files {
+			createdAt := time.Unix(f.CreatedAt, 0).Format(time.DateTime)
+			var errMsg string
+			if f.LastError != nil {
+				errMsg = f.LastError.Error()
+			}
+			fmt.Printf("%s\t%s\t%s\t%d\t%s\n", f.ID, createdAt, f.Status, f.UsageBytes, errMsg)
+		}
+	}
+	if hasMore {
+		fmt.Printf("More results available. Use --limit=%d --after=%s to retrieve.\n", limit, lastID)
+	}
+	return nil
+}
+
+// add is the handler for the "vector add" command.
+func (c *VectorCommand) add(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	storeID := args[0]
+	attributes, _ := cmd.Flags().GetStringToString("attribute")
+	wait, _ := cmd.Flags().GetInt("wait")
+
+	// Identify the request parameters
+	request := openai.VectorStoreFileRequest{
+		FileIDs: args[1:],
+	}
+	if len(attributes) > 0 {
+		request.Attributes = make(map[string]any, len(attributes))
+		for k, v := range attributes {
+			request.Attributes[k] = v
+		}
+	}
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		b, e := c.apiClient.CreateVectorStoreFileBatchRaw(ctx, storeID, request)
+		if len(b) > 0 {
+			fmt.Print(string(b))
+		}
+		return e
+	}
+
+	// Create the file batch, and optionally wait for it to finish processing
+	batch, err := c.apiClient.CreateVectorStoreFileBatch(ctx, storeID, request)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created vector store %s file batch %s: %s\n", storeID, batch.ID, batch.Status)
+	if wait > 0 {
+		return c.monitorFileBatch(ctx, storeID, batch.ID, wait)
+	}
+	fmt.Println("Use the following command to monitor progress:")
+	fmt.Printf("gpt vector batch %s %s -w 5\n", storeID, batch.ID)
+	return nil
+}
+
+// remove is the handler for the "vector remove" command.
+func (c *VectorCommand) remove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	storeID := args[0]
+	for _, fileID := range args[1:] {
+		body, err := c.apiClient.DeleteVectorStoreFileRaw(ctx, storeID, fileID)
+		if err != nil {
+			return err
+		}
+		if body != nil && c.raw {
+			fmt.Print(string(body))
+		} else {
+			fmt.Println("Removed:", fileID)
+		}
+	}
+	return nil
+}
+
+// batch is the handler for the "vector batch" command.
+func (c *VectorCommand) batch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	storeID := args[0]
+	batchID := args[1]
+	wait, _ := cmd.Flags().GetInt("wait")
+	cancel, _ := cmd.Flags().GetBool("cancel")
+
+	// Cancel the file batch?
+	if cancel {
+		if c.raw {
+			body, e := c.apiClient.CancelVectorStoreFileBatchRaw(ctx, storeID, batchID)
+			if body != nil {
+				fmt.Print(string(body))
+			}
+			return e
+		}
+		batch, err := c.apiClient.CancelVectorStoreFileBatch(ctx, storeID, batchID)
+		if err != nil {
+			return err
+		}
+		fmt.Println(batch.Progress())
+		return nil
+	}
+
+	// Monitor the file batch?
+	if wait > 0 {
+		return c.monitorFileBatch(ctx, storeID, batchID, wait)
+	}
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		body, e := c.apiClient.ReadVectorStoreFileBatchRaw(ctx, storeID, batchID)
+		if body != nil {
+			fmt.Print(string(body))
+		}
+		return e
+	}
+	batch, err := c.apiClient.ReadVectorStoreFileBatch(ctx, storeID, batchID)
+	if err != nil {
+		return err
+	}
+	j, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling VectorStoreFileBatch JSON: %w", err)
+	}
+	fmt.Println(string(j))
+	return nil
+}
+
+// search is the handler for the "vector search" command.
+func (c *VectorCommand) search(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	storeID := args[0]
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	threshold, _ := cmd.Flags().GetFloat64("score-threshold")
+	rewrite, _ := cmd.Flags().GetBool("rewrite")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	// Identify the request parameters
+	request := openai.VectorStoreSearchRequest{
+		Query:         args[1],
+		MaxNumResults: maxResults,
+		RewriteQuery:  rewrite,
+	}
+	if threshold > 0 {
+		request.RankingOptions = &openai.RankingOptions{ScoreThreshold: threshold}
+	}
+
+	// Retrieve the raw OpenAI response?
+	if c.raw {
+		b, e := c.apiClient.SearchVectorStoreRaw(ctx, storeID, request)
+		if len(b) > 0 {
+			fmt.Print(string(b))
+		}
+		return e
+	}
+
+	// Search the vector store
+	results, err := c.apiClient.SearchVectorStore(ctx, storeID, request)
+	if err != nil {
+		return err
+	}
+	if verbose {
+		j, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling VectorStoreSearchResults JSON: %w", err)
+		}
+		fmt.Println(string(j))
+		return nil
+	}
+	if len(results.Data) == 0 {
+		fmt.Println("No results found.")
+		return nil
+	}
+	for i, r := range results.Data {
+		fmt.Printf("--------------------\n%d. %s (%s) score=%.3f\n", i+1, r.FileName, r.FileID, r.Score)
+		fmt.Println(strings.TrimSpace(r.Text()))
+	}
+	return nil
+}
+
+// monitorFileBatch polls a vector store file batch until it has finished processing.
+func (c *VectorCommand) monitorFileBatch(ctx context.Context, storeID, batchID string, wait int) error {
+	fmt.Println("polling for file processing... (Ctrl+C to cancel)")
+	for {
+		batch, err := c.apiClient.ReadVectorStoreFileBatch(ctx, storeID, batchID)
+		if err != nil {
+			return err
+		}
+		fmt.Println(batch.Progress())
+		if batch.IsDone() {
+			if batch.Status != "completed" || batch.FileCounts.Failed > 0 {
+				fmt.Printf("Use the following command to review failed files:\ngpt vector files %s -s failed\n", storeID)
+			}
+			return nil
+		}
+		time.Sleep(time.Duration(wait) * time.Second)
+	}
+}
@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gpt/openai"
+	"gpt/psy"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxWebhookBytes is the maximum size of a webhook request body.
+const maxWebhookBytes = 1 << 20
+
+// WebhookCommand is the command for receiving OpenAI webhook events.
+type WebhookCommand struct {
+	apiClient *openai.Client
+	rootCmd   *cobra.Command
+	baseCmd   *cobra.Command
+	serveCmd  *cobra.Command
+	mu        sync.Mutex      // serializes event processing
+	seenMu    sync.Mutex      // guards seen
+	seen      map[string]bool // webhook IDs being processed (false) or processed (true); deliveries may be retried
+}
+
+// NewWebhookCommand creates and initializes the webhook commands.
+func NewWebhookCommand(apiClient *openai.Client, root *cobra.Command) *WebhookCommand {
+	// Base Command
+	c := &WebhookCommand{
+		apiClient: apiClient,
+		rootCmd:   root,
+		seen:      make(map[string]bool),
+	}
+	c.baseCmd = &cobra.Command{
+		Use:   "webhook",
+		Short: "Receive OpenAI webhook events",
+		Long:  "Receive OpenAI webhook events, as an alternative to polling for batch and fine-tuning job status.",
+	}
+	c.rootCmd.AddCommand(c.baseCmd)
+
+	// Serve Command
+	// Example: gpt webhook serve --addr :8080 --path /webhook
+	c.serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a webhook event receiver",
+		Long: "Serve a local HTTP webhook receiver that verifies event signatures with the webhook secret\n" +
+			"(OPENAI_WEBHOOK_SECRET) and dispatches batch and fine-tuning job events. When a batch\n" +
+			"completes, its results are processed as with the \"chat results\" command. Events that\n" +
+			"fail processing are answered with an error, so that OpenAI delivers them again. The receiver\n" +
+			"must be reachable by OpenAI (e.g. through a tunnel) at the URL configured for the webhook.",
+		Args: cobra.NoArgs,
+		RunE: c.serve,
+	}
+	c.serveCmd.Flags().StringP("addr", "a", ":8080", "Listen address")
+	c.serveCmd.Flags().StringP("path", "p", "/webhook", "Webhook URL path")
+	c.serveCmd.Flags().DurationP("tolerance", "t", openai.DefaultWebhookTolerance, "Maximum webhook timestamp age")
+	c.baseCmd.AddCommand(c.serveCmd)
+
+	return c
+}
+
+// serve is the handler for the "webhook serve" command.
+func (c *WebhookCommand) serve(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	path, _ := cmd.Flags().GetString("path")
+	tolerance, _ := cmd.Flags().GetDuration("tolerance")
+	if c.apiClient.WebhookSecret == "" {
+		return errors.New("webhook secret not found: set OPENAI_WEBHOOK_SECRET")
+	}
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t{}") {
+		return fmt.Errorf("invalid webhook path %q: expecting a URL path, e.g. /webhook", path)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Events are processed before they're acknowledged, so that OpenAI redelivers
+	// an event that failed processing:
+	var wg sync.WaitGroup
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+		if err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		event, err := openai.UnwrapWebhook(c.apiClient.WebhookSecret, r.Header, body, tolerance)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "rejected webhook:", err)
+			http.Error(w, "invalid webhook signature", http.StatusBadRequest)
+			return
+		}
+		webhookID := r.Header.Get("webhook-id")
+		first, busy := c.firstDelivery(webhookID)
+		if busy {
+			http.Error(w, "event in progress", http.StatusConflict)
+			return
+		}
+		if !first {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		wg.Add(1)
+		defer wg.Done()
+		err = c.dispatch(context.WithoutCancel(ctx), event)
+		c.processed(webhookID, err)
+		if err != nil {
+			http.Error(w, "event processing failed", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	// Shut down gracefully when interrupted, waiting for events in progress:
+	errs := make(chan error, 1)
+	go func() { errs <- server.ListenAndServe() }()
+	fmt.Printf("listening for webhook events on %s%s (Ctrl+C to stop)\n", addr, path)
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	fmt.Println("shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	wg.Wait()
+	return err
+}
+
+// firstDelivery returns true if the webhook ID has not been processed successfully
+// before, and is not being processed (busy).
+func (c *WebhookCommand) firstDelivery(webhookID string) (first, busy bool) {
+	c.seenMu.Lock()
+	defer c.seenMu.Unlock()
+	if done, ok := c.seen[webhookID]; ok {
+		return false, !done
+	}
+	c.seen[webhookID] = false
+	return true, false
+}
+
+// processed records the outcome of processing a webhook delivery. If processing
+// failed, the redelivered event is processed again.
+func (c *WebhookCommand) processed(webhookID string, err error) {
+	c.seenMu.Lock()
+	defer c.seenMu.Unlock()
+	if err != nil {
+		delete(c.seen, webhookID)
+		return
+	}
+	c.seen[webhookID] = true
+}
+
+// dispatch processes a webhook event. Errors are reported, but don't stop the receiver.
+func (c *WebhookCommand) dispatch(ctx context.Context, event openai.WebhookEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Println(event.String())
+	var err error
+	switch {
+	case event.Type == openai.EventBatchCompleted:
+		err = c.batchCompleted(ctx, event.Data.ID)
+	case event.IsBatch():
+		err = c.batchFailed(ctx, event.Data.ID)
+	case event.IsFineTuning():
+		err = c.fineTuneDone(ctx, event.Data.ID)
+	default:
+		fmt.Println("ignored event type:", event.Type)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error processing event %s: %v\n", event.ID, err)
+	}
+	return err
+}
+
+// batchCompleted processes the results of a completed batch, once every batch in its run is done.
+func (c *WebhookCommand) batchCompleted(ctx context.Context, batchID string) error {
+	run, err := psy.ReadRun(ctx, c.apiClient, batchID)
+	if err != nil {
+		return err
+	}
+	if !run.IsDone() {
+		fmt.Println(run.Progress())
+		return nil
+	}
+	return processRunResults(ctx, c.apiClient, run)
+}
+
+// batchFailed reports a batch that failed, expired, or was cancelled. If every batch
+// in its run is done, any partial results are processed.
+func (c *WebhookCommand) batchFailed(ctx context.Context, batchID string) error {
+	b, err := c.apiClient.ReadBatch(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	fmt.Println(b.Progress())
+	for _, e := range b.Errors.Data {
+		fmt.Println(e.Error())
+	}
+	run, err := psy.ReadRun(ctx, c.apiClient, batchID)
+	if err != nil {
+		return err
+	}
+	if !run.IsDone() {
+		fmt.Println(run.Progress())
+		return nil
+	}
+	for _, m := range run.Batches {
+		if m.OutputFileID != "" || m.ErrorFileID != "" {
+			return processRunResults(ctx, c.apiClient, run)
+		}
+	}
+	return nil
+}
+
+// fineTuneDone reports a fine-tuning job that succeeded, failed, or was cancelled.
+func (c *WebhookCommand) fineTuneDone(ctx context.Context, jobID string) error {
+	job, err := c.apiClient.ReadFineTune(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("fine-tuning job %s %s: %s\n", job.ID, job.Status, job.Name())
+	if job.Error.Message != "" {
+		fmt.Println("error:", job.Error.Message)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"gpt/cli"
 	"gpt/openai"
+	"io/fs"
 	"os"
 
 	"github.com/spf13/viper"
@@ -13,6 +15,9 @@ import (
 func main() {
 	var orgID string
 	var apiKey string
+	var adminKey string
+	var webhookSecret string
+	catalogPath := os.Getenv("GPT_MODEL_CATALOG")
 
 	// Application configuration
 	viper.SetConfigFile(".env")
@@ -21,10 +26,32 @@ func main() {
 	if err == nil {
 		orgID = viper.GetString("OPENAI_ORG_ID")
 		apiKey = viper.GetString("OPENAI_API_KEY")
+		adminKey = viper.GetString("OPENAI_ADMIN_KEY")
+		webhookSecret = viper.GetString("OPENAI_WEBHOOK_SECRET")
+		if viper.IsSet("GPT_MODEL_CATALOG") {
+			catalogPath = viper.GetString("GPT_MODEL_CATALOG")
+		}
+	}
+
+	// Supplement the embedded model catalog with a user-provided catalog:
+	if catalogPath == "" {
+		catalogPath = openai.DefaultCatalogPath()
+	}
+	if catalogPath != "" {
+		err = openai.Registry.LoadFile(catalogPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(os.Stderr, "warning:", err)
+		}
 	}
 
 	// Initialize the API client:
 	apiClient := openai.NewClient(orgID, apiKey)
+	if adminKey != "" {
+		apiClient.AdminKey = adminKey
+	}
+	if webhookSecret != "" {
+		apiClient.WebhookSecret = webhookSecret
+	}
 
 	// Initialize the Command Line Interface:
 	rootCmd := cli.NewRootCommand(apiClient)
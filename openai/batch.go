@@ -1,10 +1,22 @@
 package openai
 
 import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
+// Batch input file limits. Larger runs must be split into multiple batches.
+const (
+	MaxBatchRequests = 50_000    // maximum number of requests in a batch input file
+	MaxBatchBytes    = 200 << 20 // maximum size of a batch input file (200 MB)
+)
+
 // Batch provides information about an OpenAI batch, used for processing a large
 // collection of asynchronous API requests (e.g. Chat completions).
 // As a request, the fields InputFileID, Endpoint, and CompletionWindow are required.
@@ -16,8 +28,9 @@ type Batch struct {
 	Object string `json:"object,omitempty"`
 
 	// InputFileID is the ID of the JSONL input file containing the API requests.
-	// The file must have a purpose of "batch", contain a max of 50,000 JSON objects,
-	// and be no larger than 100 MB in size. This field is required.
+	// The file must have a purpose of "batch", contain a max of 50,000 JSON objects
+	// (MaxBatchRequests), and be no larger than 200 MB (MaxBatchBytes) in size.
+	// This field is required.
 	InputFileID string `json:"input_file_id"`
 
 	// Endpoint is the API endpoint for the batched requests.
@@ -178,8 +191,8 @@ type BatchList struct {
 // BatchRequest contains the just the fields required to create a batch.
 type BatchRequest struct {
 	// InputFileID is the ID of the JSONL input file containing the API requests.
-	// The file must have a purpose of "batch", contain a max of 50,000 JSON objects,
-	// and be no larger than 100 MB in size.
+	// The file must have a purpose of "batch", contain a max of 50,000 JSON objects
+	// (MaxBatchRequests), and be no larger than 200 MB (MaxBatchBytes) in size.
 	InputFileID string `json:"input_file_id"`
 
 	// Endpoint is the API endpoint for the batched requests.
@@ -196,9 +209,10 @@ type BatchRequest struct {
 }
 
 // BatchRequestItem contains information about an individual API request in a batch.
-// The batch input file will contain lines of these request JSON objects.
-// This implementation assumes the request will be a Chat Completion request.
-// Note that the API supports other types of requests as well (e.g. embeddings).
+// The batch input file will contain lines of these request JSON objects. The body
+// is a request for the batch endpoint, e.g. a ChatRequest for "/v1/chat/completions",
+// an EmbeddingRequest for "/v1/embeddings", a ResponseRequest for "/v1/responses",
+// or a ModerationRequest for "/v1/moderations".
 type BatchRequestItem struct {
 	// CustomID is a developer-provided per-request id that will be used to match outputs to inputs.
 	// It must be unique for each request in the batch.
@@ -211,13 +225,33 @@ type BatchRequestItem struct {
 	URL string `json:"url"`
 
 	// Body is the HTTP request body to be submitted.
-	Body ChatRequest `json:"body"`
+	Body json.RawMessage `json:"body"`
+}
+
+// NewBatchRequestItem creates a POST BatchRequestItem for the specified endpoint URL,
+// with the provided request body (e.g. a ChatRequest).
+func NewBatchRequestItem(customID, url string, body any) (BatchRequestItem, error) {
+	item := BatchRequestItem{
+		CustomID: customID,
+		Method:   "POST",
+		URL:      url,
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return item, fmt.Errorf("batch request item %s: marshal body: %w", customID, err)
+	}
+	item.Body = b
+	return item, nil
+}
+
+// ChatRequest decodes the body of a "/v1/chat/completions" request.
+func (r BatchRequestItem) ChatRequest() (ChatRequest, error) {
+	return DecodeBatchBody[ChatRequest](r.Body)
 }
 
 // BatchResponseItem contains information about an individual API response in a batch.
 // The batch output and error files will contain lines of these response JSON objects.
-// This implementation assumes the response will be a Chat Completion response.
-// Note that the API supports other types of responses as well (e.g. embeddings).
+// Use the typed decoders of the Response (e.g. ChatResponse) to read the body.
 type BatchResponseItem struct {
 	// ID is the OpenAI response ID, e.g. "batch_req_6p9XYPYSTTRi0xEviKjjilqrWU2Ve".
 	ID string `json:"id"`
@@ -237,12 +271,88 @@ func (r BatchResponseItem) HasError() bool {
 	return r.Error.HasError()
 }
 
-// Completion provides the first message content from the response.
+// Succeeded returns true if the request completed successfully. Requests that
+// failed have an error or an unsuccessful HTTP status code (e.g. 400).
+func (r BatchResponseItem) Succeeded() bool {
+	return !r.HasError() && r.Response.StatusCode == 200
+}
+
+// Completion provides the generated text from a chat completions or responses
+// response: the first message content, or the output text, respectively.
 func (r BatchResponseItem) Completion() string {
-	if len(r.Response.Body.Choices) > 0 {
-		return r.Response.Body.Choices[0].Message.Content
+	var body struct {
+		Choices []MessageChoice  `json:"choices"`
+		Output  []ResponseOutput `json:"output"`
+	}
+	if err := json.Unmarshal(r.Response.Body, &body); err != nil {
+		return ""
+	}
+	if len(body.Choices) > 0 {
+		return body.Choices[0].Message.Content
+	}
+	return Response{Output: body.Output}.OutputText()
+}
+
+// BatchOutcome summarizes a batch response, whether from the chat completions or
+// the responses API, or an error.
+type BatchOutcome struct {
+	StatusCode   int    // HTTP status code, e.g. 200
+	Completion   string // generated text
+	FinishReason string // e.g. "stop" or "length" (or the response status or incomplete reason)
+	Usage        Usage  // token usage
+	ErrorCode    string // error code, if the request failed
+	ErrorMessage string // error message, if the request failed
+}
+
+// Outcome summarizes the response (or error) of a batch request.
+func (r BatchResponseItem) Outcome() BatchOutcome {
+	o := BatchOutcome{StatusCode: r.Response.StatusCode}
+	if r.HasError() {
+		o.ErrorCode = r.Error.Code
+		o.ErrorMessage = r.Error.Message
+		return o
+	}
+	var body struct {
+		Choices           []MessageChoice  `json:"choices"`
+		Output            []ResponseOutput `json:"output"`
+		Status            string           `json:"status"`
+		IncompleteDetails struct {
+			Reason string `json:"reason"`
+		} `json:"incomplete_details"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			InputTokens      int `json:"input_tokens"`
+			OutputTokens     int `json:"output_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+		} `json:"usage"`
+		Error *APIError `json:"error"`
+	}
+	if len(r.Response.Body) > 0 {
+		if err := json.Unmarshal(r.Response.Body, &body); err != nil {
+			o.ErrorMessage = fmt.Sprintf("decode response body: %v", err)
+			return o
+		}
+	}
+	if body.Error != nil {
+		if body.Error.Code != nil {
+			o.ErrorCode = *body.Error.Code
+		}
+		o.ErrorMessage = body.Error.Error()
 	}
-	return ""
+	if len(body.Choices) > 0 {
+		o.Completion = body.Choices[0].Message.Content
+		o.FinishReason = body.Choices[0].FinishReason
+	} else {
+		o.Completion = Response{Output: body.Output}.OutputText()
+		o.FinishReason = cmp.Or(body.IncompleteDetails.Reason, body.Status)
+	}
+	o.Usage = Usage{
+		PromptTokens:     cmp.Or(body.Usage.PromptTokens, body.Usage.InputTokens),
+		CompletionTokens: cmp.Or(body.Usage.CompletionTokens, body.Usage.OutputTokens),
+		TotalTokens:      body.Usage.TotalTokens,
+	}
+	return o
 }
 
 // BatchItemResponse contains the HTTP response output for a batch request item.
@@ -254,5 +364,172 @@ type BatchItemResponse struct {
 	RequestID string `json:"request_id"`
 
 	// Body is the response body content.
-	Body ChatResponse `json:"body"`
+	Body json.RawMessage `json:"body"`
+}
+
+// ChatResponse decodes the body of a "/v1/chat/completions" response.
+func (r BatchItemResponse) ChatResponse() (ChatResponse, error) {
+	return DecodeBatchBody[ChatResponse](r.Body)
+}
+
+// EmbeddingResponse decodes the body of a "/v1/embeddings" response.
+func (r BatchItemResponse) EmbeddingResponse() (EmbeddingResponse, error) {
+	return DecodeBatchBody[EmbeddingResponse](r.Body)
+}
+
+// ModerationResponse decodes the body of a "/v1/moderations" response.
+func (r BatchItemResponse) ModerationResponse() (ModerationResponse, error) {
+	return DecodeBatchBody[ModerationResponse](r.Body)
+}
+
+// Response decodes the body of a "/v1/responses" response.
+func (r BatchItemResponse) Response() (Response, error) {
+	return DecodeBatchBody[Response](r.Body)
+}
+
+// DecodeBatchBody decodes a batch request or response body into the specified type.
+func DecodeBatchBody[T any](body json.RawMessage) (T, error) {
+	var v T
+	if len(body) == 0 {
+		return v, errors.New("decode batch body: empty body")
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return v, fmt.Errorf("decode batch body: %w", err)
+	}
+	return v, nil
+}
+
+// BatchInputError identifies an invalid line of a batch input file. Line 0
+// indicates a problem with the file as a whole (e.g. too many requests).
+type BatchInputError struct {
+	Line     int    // line number, starting at 1
+	CustomID string // custom_id of the request, if known
+	Message  string // description of the problem
+}
+
+// Error provides a string representation of the BatchInputError.
+func (e BatchInputError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	if e.CustomID != "" {
+		return fmt.Sprintf("line %d (%s): %s", e.Line, e.CustomID, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// BatchInput summarizes a batch input file, as validated by ValidateBatchInput.
+type BatchInput struct {
+	Requests int               // number of requests
+	Bytes    int               // file size in bytes
+	Endpoint string            // endpoint URL of the requests, e.g. "/v1/chat/completions"
+	Model    string            // model ID of the requests, e.g. "gpt-4o-mini"
+	Errors   []BatchInputError // problems found, in line order
+}
+
+// IsValid returns true if no problems were found in the batch input file.
+func (b BatchInput) IsValid() bool {
+	return len(b.Errors) == 0
+}
+
+// ValidateBatchInput checks a JSONL batch input file before it's uploaded, reporting
+// every problem found: invalid JSON, missing or duplicate custom IDs, unsupported or
+// inconsistent endpoints and models, request bodies that are invalid for the endpoint,
+// and files that exceed the batch input limits (MaxBatchRequests and MaxBatchBytes).
+func ValidateBatchInput(data []byte) BatchInput {
+	input := BatchInput{Bytes: len(data)}
+	fail := func(line int, customID, format string, args ...any) {
+		input.Errors = append(input.Errors, BatchInputError{Line: line, CustomID: customID, Message: fmt.Sprintf(format, args...)})
+	}
+	ids := make(map[string]int)
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		n := i + 1
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		input.Requests++
+		var item BatchRequestItem
+		if err := json.Unmarshal(line, &item); err != nil {
+			fail(n, "", "invalid JSON: %v", err)
+			continue
+		}
+
+		// Validate the request envelope:
+		id := item.CustomID
+		if id == "" {
+			fail(n, id, "missing custom_id")
+		} else if first, ok := ids[id]; ok {
+			fail(n, id, "duplicate custom_id (first used on line %d)", first)
+		} else {
+			ids[id] = n
+		}
+		if item.Method != "POST" {
+			fail(n, id, "invalid method %q: expecting POST", item.Method)
+		}
+		if !slices.Contains(BatchEndpoints, item.URL) {
+			fail(n, id, "unsupported url %q: expecting %s", item.URL, strings.Join(BatchEndpoints, ", "))
+			continue
+		}
+		if input.Endpoint == "" {
+			input.Endpoint = item.URL
+		} else if item.URL != input.Endpoint {
+			fail(n, id, "inconsistent url %s: expecting %s", item.URL, input.Endpoint)
+			continue
+		}
+
+		// Validate the request body for the endpoint:
+		var body struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    Role            `json:"role"`
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+			Input json.RawMessage `json:"input"`
+		}
+		if len(item.Body) == 0 || bytes.Equal(item.Body, []byte("null")) {
+			fail(n, id, "missing body")
+			continue
+		}
+		if err := json.Unmarshal(item.Body, &body); err != nil {
+			fail(n, id, "invalid body: %v", err)
+			continue
+		}
+		if body.Model == "" && item.URL != EndpointModerations {
+			fail(n, id, "missing body model")
+		} else if input.Model == "" {
+			input.Model = body.Model
+		} else if body.Model != input.Model {
+			fail(n, id, "inconsistent model %s: expecting %s", body.Model, input.Model)
+		}
+		switch item.URL {
+		case EndpointChat:
+			if len(body.Messages) == 0 {
+				fail(n, id, "missing body messages")
+			}
+			for j, m := range body.Messages {
+				if m.Role == "" {
+					fail(n, id, "message %d: missing role", j+1)
+				} else if !m.Role.IsValid() {
+					fail(n, id, "message %d: invalid role %s", j+1, m.Role)
+				}
+			}
+		default:
+			if len(body.Input) == 0 || bytes.Equal(body.Input, []byte("null")) {
+				fail(n, id, "missing body input")
+			}
+		}
+	}
+
+	// Validate the file as a whole:
+	if input.Requests == 0 {
+		fail(0, "", "no requests found")
+	}
+	if input.Requests > MaxBatchRequests {
+		fail(0, "", "too many requests: %d (maximum %d)", input.Requests, MaxBatchRequests)
+	}
+	if input.Bytes > MaxBatchBytes {
+		fail(0, "", "file too large: %d bytes (maximum %d)", input.Bytes, MaxBatchBytes)
+	}
+	return input
 }
@@ -0,0 +1,113 @@
+package openai
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBatchRequestItem(t *testing.T) {
+	expect := assert.New(t)
+	item, err := NewBatchRequestItem("c1", EndpointEmbeddings, EmbeddingRequest{
+		Model: "text-embedding-3-small",
+		Input: "An essay.",
+	})
+	if !expect.NoError(err) {
+		return
+	}
+	b, err := json.Marshal(item)
+	if expect.NoError(err) {
+		expect.JSONEq(`{"custom_id":"c1","method":"POST","url":"/v1/embeddings",
+			"body":{"model":"text-embedding-3-small","input":"An essay."}}`, string(b))
+	}
+	_, err = DecodeBatchBody[ChatRequest](nil)
+	expect.Error(err, "Empty body")
+}
+
+func TestBatchResponseItem(t *testing.T) {
+	expect := assert.New(t)
+	var chat, resp, embed BatchResponseItem
+	expect.NoError(json.Unmarshal([]byte(`{"custom_id":"c1","response":{"status_code":200,
+		"body":{"object":"chat.completion","choices":[{"message":{"role":"assistant","content":"Score: 3"}}]}}}`), &chat))
+	expect.NoError(json.Unmarshal([]byte(`{"custom_id":"c2","response":{"status_code":200,
+		"body":{"object":"response","output":[{"type":"reasoning","id":"rs_1"},
+		{"type":"message","id":"msg_1","content":[{"type":"output_text","text":"Score: 4"}]}]}}}`), &resp))
+	expect.NoError(json.Unmarshal([]byte(`{"custom_id":"c3","response":{"status_code":200,
+		"body":{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.1,0.2]}],
+		"usage":{"prompt_tokens":3,"total_tokens":3}}}}`), &embed))
+	expect.Equal("Score: 3", chat.Completion())
+	expect.Equal("Score: 4", resp.Completion())
+	expect.Equal("", embed.Completion())
+	e, err := embed.Response.EmbeddingResponse()
+	if expect.NoError(err) && expect.Len(e.Data, 1) {
+		expect.Equal([]float64{0.1, 0.2}, e.Data[0].Embedding)
+		expect.Equal(3, e.Usage.TotalTokens)
+	}
+}
+
+func TestBatchResponseItemOutcome(t *testing.T) {
+	expect := assert.New(t)
+	var chat, resp, failed, rejected BatchResponseItem
+	expect.NoError(json.Unmarshal([]byte(`{"custom_id":"c1","response":{"status_code":200,"body":{
+		"choices":[{"index":0,"message":{"role":"assistant","content":"Score: 4"},"finish_reason":"stop"}],
+		"usage":{"prompt_tokens":10,"completion_tokens":3,"total_tokens":13}}}}`), &chat))
+	expect.NoError(json.Unmarshal([]byte(`{"custom_id":"c2","response":{"status_code":200,"body":{
+		"status":"incomplete","incomplete_details":{"reason":"max_output_tokens"},
+		"output":[{"type":"message","content":[{"type":"output_text","text":"Score:"}]}],
+		"usage":{"input_tokens":20,"output_tokens":5,"total_tokens":25}}}}`), &resp))
+	expect.NoError(json.Unmarshal([]byte(`{"custom_id":"c3","response":{"status_code":400,"body":{
+		"error":{"message":"Invalid model","type":"invalid_request_error","code":"model_not_found"}}}}`), &failed))
+	expect.NoError(json.Unmarshal([]byte(`{"custom_id":"c4","error":{"code":"batch_expired","message":"expired"}}`), &rejected))
+
+	o := chat.Outcome()
+	expect.Equal(BatchOutcome{StatusCode: 200, Completion: "Score: 4", FinishReason: "stop",
+		Usage: Usage{PromptTokens: 10, CompletionTokens: 3, TotalTokens: 13}}, o, "Chat completion")
+	o = resp.Outcome()
+	expect.Equal("Score:", o.Completion, "Response")
+	expect.Equal("max_output_tokens", o.FinishReason)
+	expect.Equal(Usage{PromptTokens: 20, CompletionTokens: 5, TotalTokens: 25}, o.Usage)
+	o = failed.Outcome()
+	expect.Equal(400, o.StatusCode, "Failed request")
+	expect.Equal("model_not_found", o.ErrorCode)
+	expect.Equal("invalid_request_error: Invalid model", o.ErrorMessage)
+	o = rejected.Outcome()
+	expect.Equal("batch_expired", o.ErrorCode, "Batch error")
+	expect.Equal("expired", o.ErrorMessage)
+}
+
+func TestValidateBatchInput(t *testing.T) {
+	expect := assert.New(t)
+	valid := `{"custom_id":"c1","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hi"}]}}
+{"custom_id":"c2","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini","messages":[{"role":"user","content":[{"type":"text","text":"Hi"}]}]}}
+`
+	input := ValidateBatchInput([]byte(valid))
+	expect.True(input.IsValid(), input.Errors)
+	expect.Equal(2, input.Requests)
+	expect.Equal(EndpointChat, input.Endpoint)
+	expect.Equal("gpt-4o-mini", input.Model)
+
+	invalid := valid + `{"custom_id":"c1","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hi"}]}}
+{"custom_id":"c3","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o","messages":[]}}
+{"custom_id":"c4","method":"GET","url":"/v1/embeddings","body":{"model":"text-embedding-3-small","input":"Hi"}}
+{"custom_id":"c5","method":"POST","url":"/v1/completions","body":{}}
+{"custom_id":"c6",
+{"custom_id":"c7","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini","messages":"Hi"}}
+{"custom_id":"c8","method":"POST","url":"/v1/chat/completions","body":{"model":"gpt-4o-mini","messages":[{"role":"robot","content":"Hi"}]}}
+`
+	input = ValidateBatchInput([]byte(invalid))
+	expect.False(input.IsValid())
+	var lines []int
+	for _, e := range input.Errors {
+		lines = append(lines, e.Line)
+	}
+	expect.Equal([]int{3, 4, 4, 5, 5, 6, 7, 8, 9}, lines)
+	expect.Equal("line 3 (c1): duplicate custom_id (first used on line 1)", input.Errors[0].Error())
+	expect.Equal("line 4 (c3): inconsistent model gpt-4o: expecting gpt-4o-mini", input.Errors[1].Error())
+	expect.Equal("line 4 (c3): missing body messages", input.Errors[2].Error())
+	expect.Equal("line 9 (c8): message 1: invalid role robot", input.Errors[8].Error())
+
+	input = ValidateBatchInput(nil)
+	if expect.Len(input.Errors, 1, "Empty file") {
+		expect.Equal("no requests found", input.Errors[0].Error())
+	}
+}
@@ -0,0 +1,250 @@
+package openai
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed catalog.json
+var catalogJSON []byte
+
+// Registry is the registry of known models, initialized from the embedded model
+// catalog. It may be supplemented with a user-provided catalog file.
+var Registry = NewModelRegistry()
+
+func init() {
+	if err := Registry.Load(catalogJSON); err != nil {
+		panic(fmt.Errorf("embedded model catalog: %w", err))
+	}
+}
+
+// DefaultCatalogPath returns the default path of a user-provided model catalog
+// file, e.g. "~/.config/gpt/models.json" on Linux.
+func DefaultCatalogPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gpt", "models.json")
+}
+
+// ModelCatalog is a collection of model information, persisted as a JSON file.
+type ModelCatalog struct {
+	Models []ModelInfo `json:"models"`
+}
+
+// ModelInfo provides information about a model that's not available from the
+// models API: its context window, modalities, pricing, and lifecycle.
+type ModelInfo struct {
+	// ID is the model ID, e.g. "gpt-5". Dated snapshots (e.g. "gpt-5-2025-08-07")
+	// and fine-tuned models share the information of their base model ID.
+	ID string `json:"id"`
+
+	// Description is a short description of the model.
+	Description string `json:"description,omitempty"`
+
+	// ContextWindow is the maximum number of input and output tokens.
+	ContextWindow int `json:"context_window,omitempty"`
+
+	// MaxOutputTokens is the maximum number of output tokens (including reasoning tokens).
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+
+	// InputModalities is a list of supported inputs, e.g. "text", "image", "audio".
+	InputModalities []string `json:"input_modalities,omitempty"`
+
+	// OutputModalities is a list of supported outputs, e.g. "text", "embedding".
+	OutputModalities []string `json:"output_modalities,omitempty"`
+
+	// Endpoints is a list of supported API endpoints, e.g. "/v1/chat/completions".
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Reasoning is true for reasoning models (see ModelCapabilities).
+	Reasoning bool `json:"reasoning,omitempty"`
+
+	// Verbosity is true if the model accepts a verbosity parameter.
+	Verbosity bool `json:"verbosity,omitempty"`
+
+	// FineTuningContext is the maximum number of tokens in a fine-tuning example.
+	FineTuningContext int `json:"fine_tuning_context,omitempty"`
+
+	// Pricing provides the cost of using the model.
+	Pricing ModelPricing `json:"pricing,omitzero"`
+
+	// DeprecatedAt is the date (YYYY-MM-DD) that the model deprecation was announced.
+	DeprecatedAt string `json:"deprecated_at,omitempty"`
+
+	// ShutdownAt is the date (YYYY-MM-DD) that the model is no longer available.
+	ShutdownAt string `json:"shutdown_at,omitempty"`
+
+	// Replacement is the recommended replacement model ID for a deprecated model.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Supports returns true if the model supports the specified API endpoint.
+func (m ModelInfo) Supports(endpoint string) bool {
+	return slices.Contains(m.Endpoints, endpoint)
+}
+
+// IsDeprecated returns true if the model has been deprecated.
+func (m ModelInfo) IsDeprecated() bool {
+	return m.DeprecatedAt != "" || m.ShutdownAt != ""
+}
+
+// Warnings identifies reasons that the model may be unsuitable for use with the
+// specified API endpoints on the specified date: deprecation, shutdown, or a
+// lack of support for an endpoint.
+func (m ModelInfo) Warnings(now time.Time, endpoints ...string) []string {
+	var warnings []string
+	if m.IsDeprecated() {
+		w := "model " + m.ID + " is deprecated"
+		if m.ShutdownAt != "" {
+			shutdown, err := time.Parse(time.DateOnly, m.ShutdownAt)
+			if err == nil && !now.Before(shutdown) {
+				w = "model " + m.ID + " was shut down on " + m.ShutdownAt
+			} else {
+				w += " and will be shut down on " + m.ShutdownAt
+			}
+		}
+		if m.Replacement != "" {
+			w += "; use " + m.Replacement + " instead"
+		}
+		warnings = append(warnings, w)
+	}
+	for _, endpoint := range endpoints {
+		if endpoint != "" && !m.Supports(endpoint) {
+			warnings = append(warnings, "model "+m.ID+" does not support "+endpoint)
+		}
+	}
+	return warnings
+}
+
+// ModelPricing provides model prices in US dollars per million tokens.
+type ModelPricing struct {
+	Input       float64 `json:"input,omitempty"`
+	CachedInput float64 `json:"cached_input,omitempty"`
+	Output      float64 `json:"output,omitempty"`
+	BatchInput  float64 `json:"batch_input,omitempty"`
+	BatchOutput float64 `json:"batch_output,omitempty"`
+	Training    float64 `json:"training,omitempty"`
+}
+
+// Cost estimates the cost in US dollars of the specified token usage.
+func (p ModelPricing) Cost(u Usage, batch bool) float64 {
+	input, output := p.Input, p.Output
+	if batch && p.BatchInput > 0 {
+		input, output = p.BatchInput, p.BatchOutput
+	}
+	return (float64(u.PromptTokens)*input + float64(u.CompletionTokens)*output) / 1e6
+}
+
+// ModelRegistry is a concurrency-safe registry of model information, along with
+// the model IDs that have been verified with the models API.
+type ModelRegistry struct {
+	mu       sync.RWMutex
+	models   map[string]ModelInfo
+	verified map[string]bool
+}
+
+// NewModelRegistry creates an empty ModelRegistry.
+func NewModelRegistry() *ModelRegistry {
+	return &ModelRegistry{
+		models:   make(map[string]ModelInfo),
+		verified: make(map[string]bool),
+	}
+}
+
+// Load adds the models in the provided JSON catalog to the registry. Models
+// that are already registered are replaced with the new model information.
+func (r *ModelRegistry) Load(data []byte) error {
+	var catalog ModelCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("load model catalog: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, m := range catalog.Models {
+		if m.ID == "" {
+			return fmt.Errorf("load model catalog: model %d has no ID", i+1)
+		}
+		r.models[m.ID] = m
+	}
+	return nil
+}
+
+// LoadFile adds the models in the specified JSON catalog file to the registry.
+func (r *ModelRegistry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("load model catalog: %w", err)
+	}
+	if err := r.Load(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// snapshotSuffix matches the date suffix of a model snapshot ID, e.g. "-2025-08-07" or "-0125".
+var snapshotSuffix = regexp.MustCompile(`-(\d{4}-\d{2}-\d{2}|\d{4})$`)
+
+// Lookup returns the information for the specified model ID. Dated snapshots
+// and fine-tuned models are identified by their base model ID.
+func (r *ModelRegistry) Lookup(id string) (ModelInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if m, ok := r.models[id]; ok {
+		return m, true
+	}
+	base := BaseModelID(id)
+	if m, ok := r.models[base]; ok {
+		return m, true
+	}
+	m, ok := r.models[snapshotSuffix.ReplaceAllString(base, "")]
+	return m, ok
+}
+
+// Models returns the registered model information, sorted by model ID.
+func (r *ModelRegistry) Models() []ModelInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	models := make([]ModelInfo, 0, len(r.models))
+	for _, m := range r.models {
+		models = append(models, m)
+	}
+	slices.SortFunc(models, func(a, b ModelInfo) int { return strings.Compare(a.ID, b.ID) })
+	return models
+}
+
+// IsKnown returns true if the exact model ID is in the catalog or has been
+// verified. Dated snapshots and fine-tuned models that aren't in the catalog
+// must be verified with the models API, because their IDs may be mistyped.
+func (r *ModelRegistry) IsKnown(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.models[id]
+	return ok || r.verified[id]
+}
+
+// Verify records that the model ID has been verified with the models API.
+func (r *ModelRegistry) Verify(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verified[id] = true
+}
+
+// Warnings identifies reasons that the specified model may be unsuitable for use
+// with the specified API endpoints. Models not found in the catalog produce no warnings.
+func (r *ModelRegistry) Warnings(id string, endpoints ...string) []string {
+	m, ok := r.Lookup(id)
+	if !ok {
+		return nil
+	}
+	return m.Warnings(time.Now(), endpoints...)
+}
@@ -0,0 +1,77 @@
+package openai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	expect := assert.New(t)
+	m, ok := Registry.Lookup("gpt-4o-mini")
+	if expect.True(ok, "Catalog model") {
+		expect.Equal(128000, m.ContextWindow)
+		expect.True(m.Supports(EndpointBatch))
+	}
+	m, ok = Registry.Lookup("gpt-4o-mini-2024-07-18")
+	if expect.True(ok, "Dated snapshot") {
+		expect.Equal("gpt-4o-mini", m.ID)
+	}
+	m, ok = Registry.Lookup("ft:gpt-4o-mini-2024-07-18:org:suffix:abc123")
+	if expect.True(ok, "Fine-tuned model") {
+		expect.Equal("gpt-4o-mini", m.ID)
+	}
+	_, ok = Registry.Lookup("no-such-model")
+	expect.False(ok, "Unknown model")
+}
+
+func TestRegistryLoad(t *testing.T) {
+	expect := assert.New(t)
+	r := NewModelRegistry()
+	expect.NoError(r.Load([]byte(`{"models":[{"id":"custom","context_window":1000}]}`)))
+	expect.NoError(r.Load([]byte(`{"models":[{"id":"custom","context_window":2000}]}`)))
+	m, ok := r.Lookup("custom")
+	if expect.True(ok) {
+		expect.Equal(2000, m.ContextWindow, "Replaced model")
+	}
+	expect.Error(r.Load([]byte(`{"models":[{"description":"no ID"}]}`)), "Missing ID")
+	expect.Error(r.Load([]byte(`not json`)), "Invalid JSON")
+}
+
+func TestRegistryVerifyConcurrently(t *testing.T) {
+	expect := assert.New(t)
+	r := NewModelRegistry()
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "b", "c", "d"} {
+		wg.Add(2)
+		go func() { defer wg.Done(); r.Verify(id) }()
+		go func() { defer wg.Done(); r.IsKnown(id) }()
+	}
+	wg.Wait()
+	expect.True(r.IsKnown("c"))
+	expect.False(r.IsKnown("e"))
+}
+
+func TestRegistryIsKnown(t *testing.T) {
+	expect := assert.New(t)
+	expect.True(Registry.IsKnown("gpt-4o-mini"), "Catalog model")
+	expect.False(Registry.IsKnown("gpt-4o-mini-2099-01-01"), "Unverified snapshot")
+	expect.False(Registry.IsKnown("ft:gpt-4o-mini-2024-07-18:org:typo:abc123"), "Unverified fine-tuned model")
+}
+
+func TestModelWarnings(t *testing.T) {
+	expect := assert.New(t)
+	m := ModelInfo{
+		ID:          "old",
+		Endpoints:   []string{EndpointChat},
+		ShutdownAt:  "2025-07-14",
+		Replacement: "new",
+	}
+	before := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	expect.Equal([]string{"model old is deprecated and will be shut down on 2025-07-14; use new instead"}, m.Warnings(before))
+	expect.Equal([]string{"model old was shut down on 2025-07-14; use new instead"}, m.Warnings(after))
+	expect.Len(m.Warnings(before, EndpointChat, EndpointBatch), 2, "Unsupported endpoint")
+	expect.Empty(ModelInfo{ID: "ok", Endpoints: []string{EndpointChat}}.Warnings(after, EndpointChat))
+}
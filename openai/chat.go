@@ -3,6 +3,8 @@ package openai
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -36,8 +38,22 @@ type ChatRequest struct {
 
 	// MaxTokens is the maximum number of tokens to generate.
 	// The default is "infinity" (limited only by the context window size).
+	// Reasoning models reject this field in favor of MaxCompletionTokens.
 	MaxTokens int `json:"max_tokens,omitempty"`
 
+	// MaxCompletionTokens is an upper bound for the number of tokens that can
+	// be generated for a completion, including visible output tokens and
+	// reasoning tokens. It's required instead of MaxTokens for reasoning models.
+	MaxCompletionTokens int `json:"max_completion_tokens,omitempty"`
+
+	// ReasoningEffort constrains the effort on reasoning for reasoning models:
+	// "minimal", "low", "medium", or "high". The default is "medium".
+	ReasoningEffort ReasoningEffort `json:"reasoning_effort,omitempty"`
+
+	// Verbosity constrains the length of the response: "low", "medium", or
+	// "high". It's supported by the gpt-5 model family. The default is "medium".
+	Verbosity Verbosity `json:"verbosity,omitempty"`
+
 	// PresencePenalty is a floating point value between -2.0 and 2.0 that
 	// penalizes new tokens based on whether they appear in the text so far.
 	// The default is 0.0.
@@ -51,6 +67,44 @@ type ChatRequest struct {
 	// User is a unique identifier representing your end-user, which can help
 	// OpenAI to monitor and detect abuse. The default is an empty string.
 	User string `json:"user,omitempty"`
+
+	// Store indicates whether OpenAI should store the completion for later
+	// retrieval (see Client.ListStoredCompletions). The default is false.
+	Store bool `json:"store,omitempty"`
+
+	// Metadata is a map of up to 16 key-value pairs that can be used to filter
+	// stored completions. Keys are limited to 64 characters and values to 512.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Adapt adjusts the request to suit the capabilities of the requested model.
+// For reasoning models, MaxTokens is replaced with MaxCompletionTokens, the
+// unsupported sampling parameters are omitted, and system messages become
+// developer messages. For other models, the reasoning parameters are omitted.
+func (c *ChatRequest) Adapt() {
+	caps := Capabilities(c.Model)
+	if !caps.Verbosity {
+		c.Verbosity = ""
+	}
+	if !caps.Reasoning {
+		c.ReasoningEffort = ""
+		return
+	}
+	if c.MaxTokens > 0 {
+		if c.MaxCompletionTokens == 0 {
+			c.MaxCompletionTokens = c.MaxTokens
+		}
+		c.MaxTokens = 0
+	}
+	c.Temperature = 0
+	c.TopP = 0
+	c.PresencePenalty = 0
+	c.FrequencyPenalty = 0
+	for i, m := range c.Messages {
+		if m.Role == SYSTEM {
+			c.Messages[i].Role = DEVELOPER
+		}
+	}
 }
 
 // String produces a simple text display of the ChatRequest intended for console output.
@@ -62,6 +116,15 @@ func (c *ChatRequest) String() string {
 	if c.MaxTokens > 0 {
 		s += fmt.Sprintf(" max=%d", c.MaxTokens)
 	}
+	if c.MaxCompletionTokens > 0 {
+		s += fmt.Sprintf(" max=%d", c.MaxCompletionTokens)
+	}
+	if c.ReasoningEffort != "" {
+		s += fmt.Sprintf(" effort=%s", c.ReasoningEffort)
+	}
+	if c.Verbosity != "" {
+		s += fmt.Sprintf(" verbosity=%s", c.Verbosity)
+	}
 	if c.User != "" {
 		s += fmt.Sprintf(" user=%s", c.User)
 	}
@@ -75,13 +138,14 @@ func (c *ChatRequest) String() string {
 // ChatResponse provides a predicted text completion in response to a provided
 // prompt and other parameters.
 type ChatResponse struct {
-	ID                string          `json:"id"`                 // eg. "chatcmpl-6p9XYPYSTTRi0xEviKjjilqrWU2Ve"
-	Object            string          `json:"object"`             // eg. "chat.completion"
-	CreatedAt         int64           `json:"created"`            // epoch seconds, eg. 1677966478
-	Model             string          `json:"model"`              // eg. "gpt-3.5-turbo"
-	SystemFingerprint string          `json:"system_fingerprint"` // eg. "fp_4008e3b719"
-	Usage             Usage           `json:"usage"`
-	Choices           []MessageChoice `json:"choices"`
+	ID                string            `json:"id"`                 // eg. "chatcmpl-6p9XYPYSTTRi0xEviKjjilqrWU2Ve"
+	Object            string            `json:"object"`             // eg. "chat.completion"
+	CreatedAt         int64             `json:"created"`            // epoch seconds, eg. 1677966478
+	Model             string            `json:"model"`              // eg. "gpt-3.5-turbo"
+	SystemFingerprint string            `json:"system_fingerprint"` // eg. "fp_4008e3b719"
+	Usage             Usage             `json:"usage"`
+	Choices           []MessageChoice   `json:"choices"`
+	Metadata          map[string]string `json:"metadata,omitempty"` // stored completions only
 }
 
 // String provides a simple text display of the ChatResponse intended for console output.
@@ -139,3 +203,59 @@ type Usage struct {
 func (u Usage) String() string {
 	return fmt.Sprintf("prompt=%d completion=%d total=%d", u.PromptTokens, u.CompletionTokens, u.TotalTokens)
 }
+
+// StoredCompletionList is a list of stored chat completions.
+type StoredCompletionList struct {
+	Object  string         `json:"object"`   // "list" is expected
+	Data    []ChatResponse `json:"data"`     // list of stored chat completions
+	FirstID string         `json:"first_id"` // first completion ID in the collection
+	LastID  string         `json:"last_id"`  // use with the "after" query parameter
+	HasMore bool           `json:"has_more"` // true if there are more completions to retrieve
+}
+
+// StoredCompletionFilter identifies the stored chat completions to list.
+type StoredCompletionFilter struct {
+	Model    string            // model ID (optional)
+	Metadata map[string]string // metadata key-value pairs that must all match (optional)
+	Order    string            // "asc" or "desc" (default) by creation time
+	Limit    int               // number of completions to retrieve (default 20)
+	After    string            // last completion ID received, for pagination
+}
+
+// Query returns the URL-encoded query string for the StoredCompletionFilter.
+func (f StoredCompletionFilter) Query() string {
+	q := url.Values{}
+	if f.Model != "" {
+		q.Set("model", f.Model)
+	}
+	for k, v := range f.Metadata {
+		q.Set("metadata["+k+"]", v)
+	}
+	if f.Order != "" {
+		q.Set("order", f.Order)
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.After != "" {
+		q.Set("after", f.After)
+	}
+	return q.Encode()
+}
+
+// StoredMessage is a message in a stored chat completion request.
+type StoredMessage struct {
+	ID      string `json:"id"`
+	Role    Role   `json:"role"`
+	Content string `json:"content"`
+	Name    string `json:"name,omitempty"`
+}
+
+// StoredMessageList is a list of the messages in a stored chat completion request.
+type StoredMessageList struct {
+	Object  string          `json:"object"`   // "list" is expected
+	Data    []StoredMessage `json:"data"`     // list of messages
+	FirstID string          `json:"first_id"` // first message ID in the collection
+	LastID  string          `json:"last_id"`  // use with the "after" query parameter
+	HasMore bool            `json:"has_more"` // true if there are more messages to retrieve
+}
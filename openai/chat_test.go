@@ -0,0 +1,65 @@
+package openai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAdaptReasoningModel(t *testing.T) {
+	expect := assert.New(t)
+	req := ChatRequest{
+		Model: "gpt-5-mini",
+		Messages: []Message{
+			{Role: SYSTEM, Content: "You are a careful rater."},
+			{Role: USER, Content: "Rate this essay."},
+		},
+		Temperature:     0.2,
+		TopP:            0.9,
+		MaxTokens:       256,
+		ReasoningEffort: LowEffort,
+		Verbosity:       LowVerbosity,
+	}
+	req.Adapt()
+	expect.Equal(0, req.MaxTokens, "MaxTokens omitted")
+	expect.Equal(256, req.MaxCompletionTokens, "MaxCompletionTokens")
+	expect.Zero(req.Temperature, "Temperature omitted")
+	expect.Zero(req.TopP, "TopP omitted")
+	expect.Equal(LowEffort, req.ReasoningEffort)
+	expect.Equal(LowVerbosity, req.Verbosity)
+	expect.Equal(DEVELOPER, req.Messages[0].Role, "Developer message")
+	expect.Equal(USER, req.Messages[1].Role)
+}
+
+func TestAdaptConventionalModel(t *testing.T) {
+	expect := assert.New(t)
+	req := ChatRequest{
+		Model: "gpt-4o",
+		Messages: []Message{
+			{Role: SYSTEM, Content: "You are a careful rater."},
+			{Role: USER, Content: "Rate this essay."},
+		},
+		Temperature:     0.2,
+		MaxTokens:       256,
+		ReasoningEffort: HighEffort,
+		Verbosity:       HighVerbosity,
+	}
+	req.Adapt()
+	expect.Equal(256, req.MaxTokens)
+	expect.Equal(0, req.MaxCompletionTokens)
+	expect.Equal(float32(0.2), req.Temperature)
+	expect.Empty(req.ReasoningEffort, "ReasoningEffort omitted")
+	expect.Empty(req.Verbosity, "Verbosity omitted")
+	expect.Equal(SYSTEM, req.Messages[0].Role)
+}
+
+func TestCapabilities(t *testing.T) {
+	expect := assert.New(t)
+	expect.True(Capabilities("gpt-5").Reasoning)
+	expect.True(Capabilities("gpt-5-nano-2025-08-07").Verbosity)
+	expect.False(Capabilities("gpt-5-chat-latest").Reasoning)
+	expect.True(Capabilities("o4-mini").Reasoning)
+	expect.False(Capabilities("o4-mini").Verbosity)
+	expect.True(Capabilities("ft:o4-mini-2025-04-16:org::abc123").Reasoning)
+	expect.False(Capabilities("gpt-4.1-mini").Reasoning)
+	expect.Equal("gpt-4o-mini-2024-07-18", BaseModelID("ft:gpt-4o-mini-2024-07-18:org:suffix:id"))
+}
@@ -0,0 +1,83 @@
+package openai
+
+import (
+	"path"
+	"strings"
+)
+
+// CleanupFilter selects stale files, batches, or fine-tuned models for cleanup.
+// Zero values match all objects.
+type CleanupFilter struct {
+	Cutoff int64  // created at or before, in epoch seconds
+	Glob   string // file name or model ID glob pattern (see path.Match)
+	Status string // batch status (for files, of any referencing batch, or "none")
+}
+
+// created returns true if the creation time is at or before the cutoff.
+func (f CleanupFilter) created(createdAt int64) bool {
+	return f.Cutoff == 0 || createdAt <= f.Cutoff
+}
+
+// matches returns true if the name matches the glob pattern.
+func (f CleanupFilter) matches(name string) bool {
+	matched, _ := path.Match(f.Glob, name)
+	return f.Glob == "" || matched
+}
+
+// FileBatches returns the batches that reference each file (as input, output,
+// or error file), keyed by file ID.
+func FileBatches(batches []Batch) map[string][]Batch {
+	refs := make(map[string][]Batch)
+	for _, b := range batches {
+		for _, id := range []string{b.InputFileID, b.OutputFileID, b.ErrorFileID} {
+			if id != "" {
+				refs[id] = append(refs[id], b)
+			}
+		}
+	}
+	return refs
+}
+
+// SelectFiles returns the files that match the filter, given the batches that
+// reference each file (see FileBatches). Files referenced by any batch still
+// in progress are never selected.
+func SelectFiles(files []File, refs map[string][]Batch, f CleanupFilter) []File {
+	var selected []File
+	for _, file := range files {
+		batches := refs[file.ID]
+		status := f.Status == "" || (f.Status == "none" && len(batches) == 0)
+		inProgress := false
+		for _, b := range batches {
+			inProgress = inProgress || !b.IsDone()
+			status = status || b.Status == f.Status
+		}
+		if !inProgress && status && f.created(file.CreatedAt) && f.matches(file.FileName) {
+			selected = append(selected, file)
+		}
+	}
+	return selected
+}
+
+// SelectBatches returns the batches in progress that match the filter. Batches
+// that are already being cancelled are not selected.
+func SelectBatches(batches []Batch, f CleanupFilter) []Batch {
+	var selected []Batch
+	for _, b := range batches {
+		if !b.IsDone() && b.Status != "cancelling" && (f.Status == "" || b.Status == f.Status) &&
+			f.created(b.CreatedAt) {
+			selected = append(selected, b)
+		}
+	}
+	return selected
+}
+
+// SelectModels returns the fine-tuned models that match the filter.
+func SelectModels(models []Model, f CleanupFilter) []Model {
+	var selected []Model
+	for _, m := range models {
+		if strings.HasPrefix(m.ID, "ft:") && f.created(m.CreatedAt) && f.matches(m.ID) {
+			selected = append(selected, m)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,69 @@
+package openai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelectFiles(t *testing.T) {
+	expect := assert.New(t)
+	files := []File{
+		{ID: "file-1", FileName: "scores.jsonl", CreatedAt: 100},
+		{ID: "file-2", FileName: "scores.jsonl", CreatedAt: 100},
+		{ID: "file-3", FileName: "train.jsonl", CreatedAt: 100},
+		{ID: "file-4", FileName: "scores.jsonl", CreatedAt: 300},
+	}
+	// Newest first, as listed by the API; file-1 is shared by a batch in progress:
+	refs := FileBatches([]Batch{
+		{ID: "batch_3", Status: "in_progress", InputFileID: "file-1"},
+		{ID: "batch_2", Status: "failed", InputFileID: "file-2", ErrorFileID: "file-3"},
+		{ID: "batch_1", Status: "completed", InputFileID: "file-1", OutputFileID: "file-2"},
+	})
+	expect.Len(refs["file-1"], 2, "Every referencing batch")
+	ids := func(files []File) []string {
+		var ids []string
+		for _, f := range files {
+			ids = append(ids, f.ID)
+		}
+		return ids
+	}
+	expect.Equal([]string{"file-2", "file-3", "file-4"}, ids(SelectFiles(files, refs, CleanupFilter{})))
+	expect.Equal([]string{"file-2", "file-3"}, ids(SelectFiles(files, refs, CleanupFilter{Cutoff: 200})))
+	expect.Equal([]string{"file-2", "file-4"}, ids(SelectFiles(files, refs, CleanupFilter{Glob: "scores*"})))
+	expect.Equal([]string{"file-2"}, ids(SelectFiles(files, refs, CleanupFilter{Status: "completed"})))
+	expect.Equal([]string{"file-4"}, ids(SelectFiles(files, refs, CleanupFilter{Status: "none"})))
+	expect.Empty(SelectFiles(files, refs, CleanupFilter{Status: "in_progress"}), "Never in progress")
+}
+
+func TestSelectBatches(t *testing.T) {
+	expect := assert.New(t)
+	batches := []Batch{
+		{ID: "batch_1", Status: "in_progress", CreatedAt: 100},
+		{ID: "batch_2", Status: "validating", CreatedAt: 100},
+		{ID: "batch_3", Status: "cancelling", CreatedAt: 100},
+		{ID: "batch_4", Status: "completed", CreatedAt: 100},
+		{ID: "batch_5", Status: "in_progress", CreatedAt: 300},
+	}
+	expect.Len(SelectBatches(batches, CleanupFilter{}), 3)
+	expect.Len(SelectBatches(batches, CleanupFilter{Cutoff: 200}), 2)
+	selected := SelectBatches(batches, CleanupFilter{Cutoff: 200, Status: "validating"})
+	if expect.Len(selected, 1) {
+		expect.Equal("batch_2", selected[0].ID)
+	}
+}
+
+func TestSelectModels(t *testing.T) {
+	expect := assert.New(t)
+	models := []Model{
+		{ID: "gpt-4o-mini", CreatedAt: 100},
+		{ID: "ft:gpt-4o-mini:org::abc", CreatedAt: 100},
+		{ID: "ft:gpt-4.1-mini:org::def", CreatedAt: 100},
+		{ID: "ft:gpt-4o-mini:org::ghi", CreatedAt: 300},
+	}
+	expect.Len(SelectModels(models, CleanupFilter{}), 3, "Fine-tuned models only")
+	expect.Len(SelectModels(models, CleanupFilter{Cutoff: 200}), 2)
+	selected := SelectModels(models, CleanupFilter{Cutoff: 200, Glob: "ft:gpt-4o-mini:*"})
+	if expect.Len(selected, 1) {
+		expect.Equal("ft:gpt-4o-mini:org::abc", selected[0].ID)
+	}
+}
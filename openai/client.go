@@ -17,15 +17,18 @@ import (
 
 // Client is the OpenAI API client.
 type Client struct {
-	OrgID   string
-	APIKey  string
-	BaseURL string
-	client  *http.Client
+	OrgID         string
+	APIKey        string
+	AdminKey      string // used for organization administration (e.g. usage and costs)
+	WebhookSecret string // used to verify webhook event signatures
+	BaseURL       string
+	client        *http.Client
 }
 
 // NewClient instantiates a new OpenAI API client. If either orgID or apiKey
 // are not provided, the environment variables OPENAI_ORG_ID and OPENAI_API_KEY
-// will be used, respectively.
+// will be used, respectively. The admin key and webhook secret are read from
+// OPENAI_ADMIN_KEY and OPENAI_WEBHOOK_SECRET.
 func NewClient(orgID, apiKey string) *Client {
 	if orgID == "" {
 		orgID = os.Getenv("OPENAI_ORG_ID")
@@ -34,10 +37,12 @@ func NewClient(orgID, apiKey string) *Client {
 		apiKey = os.Getenv("OPENAI_API_KEY")
 	}
 	return &Client{
-		OrgID:   orgID,
-		APIKey:  apiKey,
-		BaseURL: "https://api.openai.com/v1",
-		client:  &http.Client{Timeout: 60 * time.Second},
+		OrgID:         orgID,
+		APIKey:        apiKey,
+		AdminKey:      os.Getenv("OPENAI_ADMIN_KEY"),
+		WebhookSecret: os.Getenv("OPENAI_WEBHOOK_SECRET"),
+		BaseURL:       "https://api.openai.com/v1",
+		client:        &http.Client{Timeout: 60 * time.Second},
 	}
 }
 
@@ -75,6 +80,19 @@ func (c *Client) deleteRequest(ctx context.Context, path string) (*http.Request,
 	return c.newRequest(ctx, http.MethodDelete, path, nil)
 }
 
+// adminRequest creates a new HTTP GET request for an organization administration
+// endpoint, authorized with the admin key (or the API key, if no admin key is set).
+func (c *Client) adminRequest(ctx context.Context, path string) (*http.Request, error) {
+	req, err := c.getRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if c.AdminKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AdminKey)
+	}
+	return req, nil
+}
+
 // sendRequest sends the provided HTTP request and returns the response body.
 func (c *Client) sendRequest(req *http.Request) ([]byte, error) {
 	resp, err := c.client.Do(req)
@@ -170,17 +188,19 @@ func (c *Client) ReadModel(ctx context.Context, id string) (Model, error) {
 	return model, nil
 }
 
-// ValidModel returns true if the specified model ID is valid.
+// ValidModel returns true if the specified model ID is valid. Model IDs found
+// in the model Registry catalog are valid; other models (including dated
+// snapshots and fine-tuned models) are verified with the models API.
 func (c *Client) ValidModel(ctx context.Context, id string) bool {
-	if CommonModels[id] {
+	if Registry.IsKnown(id) {
 		return true
 	}
 	model, err := c.ReadModel(ctx, id)
-	if err != nil {
+	if err != nil || model.ID != id {
 		return false
 	}
-	CommonModels[model.ID] = true
-	return model.ID == id
+	Registry.Verify(model.ID)
+	return true
 }
 
 // DeleteModelRaw deletes the specified model. It returns the raw JSON response.
@@ -440,6 +460,9 @@ func (c *Client) ReadBatch(ctx context.Context, id string) (Batch, error) {
 	return batch, nil
 }
 
+// maxBatchLineBytes is the maximum size of a batch output line (e.g. a large embedding).
+const maxBatchLineBytes = 16 << 20
+
 // ReadBatchResponses reads the results of the specified batch job.
 func (c *Client) ReadBatchResponses(ctx context.Context, id string) (Batch, map[string]BatchResponseItem, error) {
 	// Read the batch and verify that results are available:
@@ -460,6 +483,7 @@ func (c *Client) ReadBatchResponses(ctx context.Context, id string) (Batch, map[
 		}
 		var line int
 		scanner := bufio.NewScanner(bytes.NewReader(outputBytes))
+		scanner.Buffer(nil, maxBatchLineBytes)
 		for scanner.Scan() {
 			line++
 			var item BatchResponseItem
@@ -478,6 +502,7 @@ func (c *Client) ReadBatchResponses(ctx context.Context, id string) (Batch, map[
 		}
 		var line int
 		scanner := bufio.NewScanner(bytes.NewReader(errorBytes))
+		scanner.Buffer(nil, maxBatchLineBytes)
 		for scanner.Scan() {
 			line++
 			var item BatchResponseItem
@@ -548,17 +573,10 @@ func (c *Client) CreateFineTune(ctx context.Context, req FineTuneRequest) (FineT
 	return job, nil
 }
 
-// ListFineTunesRaw lists the currently available fine-tuning jobs, and provides basic information
-// about each one, including job status events. It returns the raw JSON response.
-func (c *Client) ListFineTunesRaw(ctx context.Context, limit int, after string) ([]byte, error) {
-	if limit < 1 {
-		limit = 20
-	}
-	path := fmt.Sprintf("/fine_tuning/jobs?limit=%d", limit)
-	if after != "" {
-		path += "&after=" + after
-	}
-	req, err := c.getRequest(ctx, path)
+// ListFineTunesRaw lists the fine-tuning jobs selected by the filter, and provides basic
+// information about each one, including job status events. It returns the raw JSON response.
+func (c *Client) ListFineTunesRaw(ctx context.Context, f FineTuneFilter) ([]byte, error) {
+	req, err := c.getRequest(ctx, "/fine_tuning/jobs?"+f.Query())
 	if err != nil {
 		return nil, fmt.Errorf("list fine-tuning jobs: %w", err)
 	}
@@ -569,11 +587,11 @@ func (c *Client) ListFineTunesRaw(ctx context.Context, limit int, after string)
 	return body, nil
 }
 
-// ListFineTunes lists the currently available fine-tuning jobs, and provides basic information
-// about each one, including job status events.
-func (c *Client) ListFineTunes(ctx context.Context, limit int, after string) ([]FineTuneJob, bool, error) {
+// ListFineTunes lists the fine-tuning jobs selected by the filter, and provides basic
+// information about each one, including job status events.
+func (c *Client) ListFineTunes(ctx context.Context, f FineTuneFilter) ([]FineTuneJob, bool, error) {
 	// Fetch the raw JSON response:
-	body, err := c.ListFineTunesRaw(ctx, limit, after)
+	body, err := c.ListFineTunesRaw(ctx, f)
 	if err != nil {
 		return nil, false, err
 	}
@@ -673,6 +691,95 @@ func (c *Client) CancelFineTune(ctx context.Context, id string) (FineTuneJob, er
 	return job, nil
 }
 
+// PauseFineTuneRaw pauses the specified running fine-tuning job. It returns the raw JSON response.
+func (c *Client) PauseFineTuneRaw(ctx context.Context, id string) ([]byte, error) {
+	req, err := c.postRequest(ctx, "/fine_tuning/jobs/"+id+"/pause", nil)
+	if err != nil {
+		return nil, fmt.Errorf("pause fine-tuning job %s: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return body, fmt.Errorf("pause fine-tuning job %s: %w", id, err)
+	}
+	return body, nil
+}
+
+// PauseFineTune pauses the specified running fine-tuning job.
+func (c *Client) PauseFineTune(ctx context.Context, id string) (FineTuneJob, error) {
+	var job FineTuneJob
+	raw, err := c.PauseFineTuneRaw(ctx, id)
+	if err != nil {
+		return job, err
+	}
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return job, fmt.Errorf("pause fine-tuning job %s: unmarshal response: %w", id, err)
+	}
+	return job, nil
+}
+
+// ResumeFineTuneRaw resumes the specified paused fine-tuning job. It returns the raw JSON response.
+func (c *Client) ResumeFineTuneRaw(ctx context.Context, id string) ([]byte, error) {
+	req, err := c.postRequest(ctx, "/fine_tuning/jobs/"+id+"/resume", nil)
+	if err != nil {
+		return nil, fmt.Errorf("resume fine-tuning job %s: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return body, fmt.Errorf("resume fine-tuning job %s: %w", id, err)
+	}
+	return body, nil
+}
+
+// ResumeFineTune resumes the specified paused fine-tuning job.
+func (c *Client) ResumeFineTune(ctx context.Context, id string) (FineTuneJob, error) {
+	var job FineTuneJob
+	raw, err := c.ResumeFineTuneRaw(ctx, id)
+	if err != nil {
+		return job, err
+	}
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return job, fmt.Errorf("resume fine-tuning job %s: unmarshal response: %w", id, err)
+	}
+	return job, nil
+}
+
+// ListFineTuneCheckpointsRaw lists the checkpoints of the specified fine-tuning job.
+// It returns the raw JSON response.
+func (c *Client) ListFineTuneCheckpointsRaw(ctx context.Context, id string, limit int, after string) ([]byte, error) {
+	if limit < 1 {
+		limit = 10
+	}
+	path := fmt.Sprintf("/fine_tuning/jobs/%s/checkpoints?limit=%d", id, limit)
+	if after != "" {
+		path += "&after=" + after
+	}
+	req, err := c.getRequest(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("list fine-tuning job %s checkpoints: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list fine-tuning job %s checkpoints: %w", id, err)
+	}
+	return body, nil
+}
+
+// ListFineTuneCheckpoints lists the checkpoints of the specified fine-tuning job, with
+// their training and validation metrics.
+func (c *Client) ListFineTuneCheckpoints(ctx context.Context, id string, limit int, after string) ([]FineTuneCheckpoint, bool, string, error) {
+	// Fetch the raw JSON response:
+	body, err := c.ListFineTuneCheckpointsRaw(ctx, id, limit, after)
+	if err != nil {
+		return nil, false, "", err
+	}
+	// Unmarshal the JSON response into a list of checkpoints:
+	var list FineTuneCheckpoints
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, false, "", fmt.Errorf("list fine-tuning job %s checkpoints: unmarshal response: %w", id, err)
+	}
+	return list.Data, list.HasMore, list.LastID, nil
+}
+
 // CompleteChatRaw creates a new chat completion. It returns the raw JSON response.
 func (c *Client) CompleteChatRaw(ctx context.Context, req ChatRequest) ([]byte, error) {
 	body, err := json.Marshal(req)
@@ -702,3 +809,561 @@ func (c *Client) CompleteChat(ctx context.Context, req ChatRequest) (ChatRespons
 	}
 	return chat, nil
 }
+
+// ListStoredCompletionsRaw lists the stored chat completions that match the filter.
+// Only completions created with Store enabled are available. It returns the raw JSON response.
+func (c *Client) ListStoredCompletionsRaw(ctx context.Context, f StoredCompletionFilter) ([]byte, error) {
+	req, err := c.getRequest(ctx, "/chat/completions?"+f.Query())
+	if err != nil {
+		return nil, fmt.Errorf("list stored completions: %w", err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list stored completions: %w", err)
+	}
+	return body, nil
+}
+
+// ListStoredCompletions lists the stored chat completions that match the filter.
+func (c *Client) ListStoredCompletions(ctx context.Context, f StoredCompletionFilter) ([]ChatResponse, bool, string, error) {
+	var list StoredCompletionList
+	body, err := c.ListStoredCompletionsRaw(ctx, f)
+	if err != nil {
+		return list.Data, list.HasMore, list.LastID, err
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return list.Data, list.HasMore, list.LastID, fmt.Errorf("list stored completions: unmarshal response: %w", err)
+	}
+	return list.Data, list.HasMore, list.LastID, nil
+}
+
+// ReadStoredCompletionRaw reads the specified stored chat completion. It returns the raw JSON response.
+func (c *Client) ReadStoredCompletionRaw(ctx context.Context, id string) ([]byte, error) {
+	req, err := c.getRequest(ctx, "/chat/completions/"+id)
+	if err != nil {
+		return nil, fmt.Errorf("read stored completion %s: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("read stored completion %s: %w", id, err)
+	}
+	return body, nil
+}
+
+// ReadStoredCompletion reads the specified stored chat completion.
+func (c *Client) ReadStoredCompletion(ctx context.Context, id string) (ChatResponse, error) {
+	var completion ChatResponse
+	body, err := c.ReadStoredCompletionRaw(ctx, id)
+	if err != nil {
+		return completion, err
+	}
+	if err := json.Unmarshal(body, &completion); err != nil {
+		return completion, fmt.Errorf("read stored completion %s: unmarshal response: %w", id, err)
+	}
+	return completion, nil
+}
+
+// ListStoredMessagesRaw lists the request messages of the specified stored chat completion.
+// It returns the raw JSON response.
+func (c *Client) ListStoredMessagesRaw(ctx context.Context, id string, limit int, after string) ([]byte, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	path := fmt.Sprintf("/chat/completions/%s/messages?limit=%d", id, limit)
+	if after != "" {
+		path += "&after=" + after
+	}
+	req, err := c.getRequest(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("list stored completion %s messages: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list stored completion %s messages: %w", id, err)
+	}
+	return body, nil
+}
+
+// ListStoredMessages lists the request messages of the specified stored chat completion.
+func (c *Client) ListStoredMessages(ctx context.Context, id string, limit int, after string) ([]StoredMessage, bool, string, error) {
+	var list StoredMessageList
+	body, err := c.ListStoredMessagesRaw(ctx, id, limit, after)
+	if err != nil {
+		return list.Data, list.HasMore, list.LastID, err
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return list.Data, list.HasMore, list.LastID, fmt.Errorf("list stored completion %s messages: unmarshal response: %w", id, err)
+	}
+	return list.Data, list.HasMore, list.LastID, nil
+}
+
+// DeleteStoredCompletionRaw deletes the specified stored chat completion. It returns the raw JSON response.
+func (c *Client) DeleteStoredCompletionRaw(ctx context.Context, id string) ([]byte, error) {
+	req, err := c.deleteRequest(ctx, "/chat/completions/"+id)
+	if err != nil {
+		return nil, fmt.Errorf("delete stored completion %s: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("delete stored completion %s: %w", id, err)
+	}
+	return body, nil
+}
+
+// DeleteStoredCompletion deletes the specified stored chat completion.
+func (c *Client) DeleteStoredCompletion(ctx context.Context, id string) error {
+	_, err := c.DeleteStoredCompletionRaw(ctx, id)
+	return err
+}
+
+// CreateVectorStoreRaw creates a new vector store. It returns the raw JSON response.
+func (c *Client) CreateVectorStoreRaw(ctx context.Context, req VectorStoreRequest) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("create vector store: %w", err)
+	}
+	httpReq, err := c.postRequest(ctx, "/vector_stores", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create vector store: %w", err)
+	}
+	raw, err := c.sendRequest(httpReq)
+	if err != nil {
+		return raw, fmt.Errorf("create vector store: %w", err)
+	}
+	return raw, nil
+}
+
+// CreateVectorStore creates a new vector store.
+func (c *Client) CreateVectorStore(ctx context.Context, req VectorStoreRequest) (VectorStore, error) {
+	var store VectorStore
+	body, err := c.CreateVectorStoreRaw(ctx, req)
+	if err != nil {
+		return store, err
+	}
+	if err := json.Unmarshal(body, &store); err != nil {
+		return store, fmt.Errorf("create vector store: unmarshal response: %w", err)
+	}
+	return store, nil
+}
+
+// ListVectorStoresRaw lists the organization's vector stores, most recent first.
+// It returns the raw JSON response.
+func (c *Client) ListVectorStoresRaw(ctx context.Context, limit int, after string) ([]byte, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	path := fmt.Sprintf("/vector_stores?limit=%d", limit)
+	if after != "" {
+		path += "&after=" + after
+	}
+	req, err := c.getRequest(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("list vector stores: %w", err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list vector stores: %w", err)
+	}
+	return body, nil
+}
+
+// ListVectorStores lists the organization's vector stores, most recent first.
+func (c *Client) ListVectorStores(ctx context.Context, limit int, after string) ([]VectorStore, bool, string, error) {
+	var list VectorStoreList
+	body, err := c.ListVectorStoresRaw(ctx, limit, after)
+	if err != nil {
+		return list.Data, list.HasMore, list.LastID, err
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return list.Data, list.HasMore, list.LastID, fmt.Errorf("list vector stores: unmarshal response: %w", err)
+	}
+	return list.Data, list.HasMore, list.LastID, nil
+}
+
+// ReadVectorStoreRaw reads the details of the specified vector store. It returns the raw JSON response.
+func (c *Client) ReadVectorStoreRaw(ctx context.Context, id string) ([]byte, error) {
+	req, err := c.getRequest(ctx, "/vector_stores/"+id)
+	if err != nil {
+		return nil, fmt.Errorf("read vector store %s: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("read vector store %s: %w", id, err)
+	}
+	return body, nil
+}
+
+// ReadVectorStore reads the details of the specified vector store.
+func (c *Client) ReadVectorStore(ctx context.Context, id string) (VectorStore, error) {
+	var store VectorStore
+	body, err := c.ReadVectorStoreRaw(ctx, id)
+	if err != nil {
+		return store, err
+	}
+	if err := json.Unmarshal(body, &store); err != nil {
+		return store, fmt.Errorf("read vector store %s: unmarshal response: %w", id, err)
+	}
+	return store, nil
+}
+
+// DeleteVectorStoreRaw deletes the specified vector store. The files in the vector
+// store are not deleted. It returns the raw JSON response.
+func (c *Client) DeleteVectorStoreRaw(ctx context.Context, id string) ([]byte, error) {
+	req, err := c.deleteRequest(ctx, "/vector_stores/"+id)
+	if err != nil {
+		return nil, fmt.Errorf("delete vector store %s: %w", id, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return body, fmt.Errorf("delete vector store %s: %w", id, err)
+	}
+	return body, nil
+}
+
+// DeleteVectorStore deletes the specified vector store. The files in the vector store are not deleted.
+func (c *Client) DeleteVectorStore(ctx context.Context, id string) error {
+	_, err := c.DeleteVectorStoreRaw(ctx, id)
+	return err
+}
+
+// CreateVectorStoreFileRaw attaches an uploaded file to the specified vector store.
+// It returns the raw JSON response.
+func (c *Client) CreateVectorStoreFileRaw(ctx context.Context, storeID string, req VectorStoreFileRequest) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("create vector store %s file: %w", storeID, err)
+	}
+	httpReq, err := c.postRequest(ctx, "/vector_stores/"+storeID+"/files", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create vector store %s file: %w", storeID, err)
+	}
+	raw, err := c.sendRequest(httpReq)
+	if err != nil {
+		return raw, fmt.Errorf("create vector store %s file: %w", storeID, err)
+	}
+	return raw, nil
+}
+
+// CreateVectorStoreFile attaches an uploaded file to the specified vector store.
+func (c *Client) CreateVectorStoreFile(ctx context.Context, storeID string, req VectorStoreFileRequest) (VectorStoreFile, error) {
+	var file VectorStoreFile
+	body, err := c.CreateVectorStoreFileRaw(ctx, storeID, req)
+	if err != nil {
+		return file, err
+	}
+	if err := json.Unmarshal(body, &file); err != nil {
+		return file, fmt.Errorf("create vector store %s file: unmarshal response: %w", storeID, err)
+	}
+	return file, nil
+}
+
+// ListVectorStoreFilesRaw lists the files attached to the specified vector store.
+// If a status is provided ("in_progress", "completed", "failed", "cancelled"),
+// it will filter the list to only include files with that status.
+// It returns the raw JSON response.
+func (c *Client) ListVectorStoreFilesRaw(ctx context.Context, storeID, status string, limit int, after string) ([]byte, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	path := fmt.Sprintf("/vector_stores/%s/files?limit=%d", storeID, limit)
+	if status != "" {
+		path += "&filter=" + status
+	}
+	if after != "" {
+		path += "&after=" + after
+	}
+	req, err := c.getRequest(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("list vector store %s files: %w", storeID, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list vector store %s files: %w", storeID, err)
+	}
+	return body, nil
+}
+
+// ListVectorStoreFiles lists the files attached to the specified vector store.
+func (c *Client) ListVectorStoreFiles(ctx context.Context, storeID, status string, limit int, after string) ([]VectorStoreFile, bool, string, error) {
+	var list VectorStoreFileList
+	body, err := c.ListVectorStoreFilesRaw(ctx, storeID, status, limit, after)
+	if err != nil {
+		return list.Data, list.HasMore, list.LastID, err
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return list.Data, list.HasMore, list.LastID, fmt.Errorf("list vector store %s files: unmarshal response: %w", storeID, err)
+	}
+	return list.Data, list.HasMore, list.LastID, nil
+}
+
+// ReadVectorStoreFileRaw reads the details of a file attached to the specified vector store.
+// It returns the raw JSON response.
+func (c *Client) ReadVectorStoreFileRaw(ctx context.Context, storeID, fileID string) ([]byte, error) {
+	req, err := c.getRequest(ctx, "/vector_stores/"+storeID+"/files/"+fileID)
+	if err != nil {
+		return nil, fmt.Errorf("read vector store %s file %s: %w", storeID, fileID, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("read vector store %s file %s: %w", storeID, fileID, err)
+	}
+	return body, nil
+}
+
+// ReadVectorStoreFile reads the details of a file attached to the specified vector store.
+func (c *Client) ReadVectorStoreFile(ctx context.Context, storeID, fileID string) (VectorStoreFile, error) {
+	var file VectorStoreFile
+	body, err := c.ReadVectorStoreFileRaw(ctx, storeID, fileID)
+	if err != nil {
+		return file, err
+	}
+	if err := json.Unmarshal(body, &file); err != nil {
+		return file, fmt.Errorf("read vector store %s file %s: unmarshal response: %w", storeID, fileID, err)
+	}
+	return file, nil
+}
+
+// DeleteVectorStoreFileRaw removes a file from the specified vector store. The file
+// itself is not deleted. It returns the raw JSON response.
+func (c *Client) DeleteVectorStoreFileRaw(ctx context.Context, storeID, fileID string) ([]byte, error) {
+	req, err := c.deleteRequest(ctx, "/vector_stores/"+storeID+"/files/"+fileID)
+	if err != nil {
+		return nil, fmt.Errorf("delete vector store %s file %s: %w", storeID, fileID, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return body, fmt.Errorf("delete vector store %s file %s: %w", storeID, fileID, err)
+	}
+	return body, nil
+}
+
+// DeleteVectorStoreFile removes a file from the specified vector store. The file itself is not deleted.
+func (c *Client) DeleteVectorStoreFile(ctx context.Context, storeID, fileID string) error {
+	_, err := c.DeleteVectorStoreFileRaw(ctx, storeID, fileID)
+	return err
+}
+
+// CreateVectorStoreFileBatchRaw attaches a batch of uploaded files to the specified
+// vector store. It returns the raw JSON response.
+func (c *Client) CreateVectorStoreFileBatchRaw(ctx context.Context, storeID string, req VectorStoreFileRequest) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("create vector store %s file batch: %w", storeID, err)
+	}
+	httpReq, err := c.postRequest(ctx, "/vector_stores/"+storeID+"/file_batches", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create vector store %s file batch: %w", storeID, err)
+	}
+	raw, err := c.sendRequest(httpReq)
+	if err != nil {
+		return raw, fmt.Errorf("create vector store %s file batch: %w", storeID, err)
+	}
+	return raw, nil
+}
+
+// CreateVectorStoreFileBatch attaches a batch of uploaded files to the specified vector store.
+func (c *Client) CreateVectorStoreFileBatch(ctx context.Context, storeID string, req VectorStoreFileRequest) (VectorStoreFileBatch, error) {
+	var batch VectorStoreFileBatch
+	body, err := c.CreateVectorStoreFileBatchRaw(ctx, storeID, req)
+	if err != nil {
+		return batch, err
+	}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return batch, fmt.Errorf("create vector store %s file batch: unmarshal response: %w", storeID, err)
+	}
+	return batch, nil
+}
+
+// ReadVectorStoreFileBatchRaw reads the status of a vector store file batch.
+// It returns the raw JSON response.
+func (c *Client) ReadVectorStoreFileBatchRaw(ctx context.Context, storeID, batchID string) ([]byte, error) {
+	req, err := c.getRequest(ctx, "/vector_stores/"+storeID+"/file_batches/"+batchID)
+	if err != nil {
+		return nil, fmt.Errorf("read vector store %s file batch %s: %w", storeID, batchID, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("read vector store %s file batch %s: %w", storeID, batchID, err)
+	}
+	return body, nil
+}
+
+// ReadVectorStoreFileBatch reads the status of a vector store file batch.
+func (c *Client) ReadVectorStoreFileBatch(ctx context.Context, storeID, batchID string) (VectorStoreFileBatch, error) {
+	var batch VectorStoreFileBatch
+	body, err := c.ReadVectorStoreFileBatchRaw(ctx, storeID, batchID)
+	if err != nil {
+		return batch, err
+	}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return batch, fmt.Errorf("read vector store %s file batch %s: unmarshal response: %w", storeID, batchID, err)
+	}
+	return batch, nil
+}
+
+// CancelVectorStoreFileBatchRaw cancels a vector store file batch in progress.
+// It returns the raw JSON response.
+func (c *Client) CancelVectorStoreFileBatchRaw(ctx context.Context, storeID, batchID string) ([]byte, error) {
+	req, err := c.postRequest(ctx, "/vector_stores/"+storeID+"/file_batches/"+batchID+"/cancel", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cancel vector store %s file batch %s: %w", storeID, batchID, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return body, fmt.Errorf("cancel vector store %s file batch %s: %w", storeID, batchID, err)
+	}
+	return body, nil
+}
+
+// CancelVectorStoreFileBatch cancels a vector store file batch in progress.
+func (c *Client) CancelVectorStoreFileBatch(ctx context.Context, storeID, batchID string) (VectorStoreFileBatch, error) {
+	var batch VectorStoreFileBatch
+	raw, err := c.CancelVectorStoreFileBatchRaw(ctx, storeID, batchID)
+	if err != nil {
+		return batch, err
+	}
+	if err := json.Unmarshal(raw, &batch); err != nil {
+		return batch, fmt.Errorf("cancel vector store %s file batch %s: unmarshal response: %w", storeID, batchID, err)
+	}
+	return batch, nil
+}
+
+// SearchVectorStoreRaw searches the specified vector store for relevant chunks of
+// content, based on a query and an optional attribute filter. It returns the raw JSON response.
+func (c *Client) SearchVectorStoreRaw(ctx context.Context, storeID string, req VectorStoreSearchRequest) ([]byte, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("search vector store %s: %w", storeID, err)
+	}
+	httpReq, err := c.postRequest(ctx, "/vector_stores/"+storeID+"/search", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("search vector store %s: %w", storeID, err)
+	}
+	raw, err := c.sendRequest(httpReq)
+	if err != nil {
+		return raw, fmt.Errorf("search vector store %s: %w", storeID, err)
+	}
+	return raw, nil
+}
+
+// SearchVectorStore searches the specified vector store for relevant chunks of
+// content, based on a query and an optional attribute filter.
+func (c *Client) SearchVectorStore(ctx context.Context, storeID string, req VectorStoreSearchRequest) (VectorStoreSearchResults, error) {
+	var results VectorStoreSearchResults
+	raw, err := c.SearchVectorStoreRaw(ctx, storeID, req)
+	if err != nil {
+		return results, err
+	}
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return results, fmt.Errorf("search vector store %s: unmarshal response: %w", storeID, err)
+	}
+	return results, nil
+}
+
+// ListUsageRaw lists the organization's usage for the specified usage type: "completions",
+// "embeddings", "moderations", "images", "audio_speeches", "audio_transcriptions",
+// or "vector_stores". It requires an admin key. It returns the raw JSON response.
+func (c *Client) ListUsageRaw(ctx context.Context, usageType string, r UsageRequest) ([]byte, error) {
+	req, err := c.adminRequest(ctx, "/organization/usage/"+usageType+"?"+r.Query())
+	if err != nil {
+		return nil, fmt.Errorf("list %s usage: %w", usageType, err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list %s usage: %w", usageType, err)
+	}
+	return body, nil
+}
+
+// ListUsage lists the organization's usage for the specified usage type. It returns
+// a page of time buckets, whether there are more, and the next page cursor.
+func (c *Client) ListUsage(ctx context.Context, usageType string, r UsageRequest) ([]UsageBucket, bool, string, error) {
+	var page UsagePage
+	body, err := c.ListUsageRaw(ctx, usageType, r)
+	if err != nil {
+		return page.Data, page.HasMore, page.NextPage, err
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return page.Data, page.HasMore, page.NextPage, fmt.Errorf("list %s usage: unmarshal response: %w", usageType, err)
+	}
+	return page.Data, page.HasMore, page.NextPage, nil
+}
+
+// ListCompletionsUsage lists the organization's chat completions usage.
+func (c *Client) ListCompletionsUsage(ctx context.Context, r UsageRequest) ([]UsageBucket, bool, string, error) {
+	return c.ListUsage(ctx, "completions", r)
+}
+
+// ListEmbeddingsUsage lists the organization's embeddings usage.
+func (c *Client) ListEmbeddingsUsage(ctx context.Context, r UsageRequest) ([]UsageBucket, bool, string, error) {
+	return c.ListUsage(ctx, "embeddings", r)
+}
+
+// ListBatchUsage lists the organization's chat completions usage from batch jobs.
+func (c *Client) ListBatchUsage(ctx context.Context, r UsageRequest) ([]UsageBucket, bool, string, error) {
+	batch := true
+	r.Batch = &batch
+	return c.ListUsage(ctx, "completions", r)
+}
+
+// ListCostsRaw lists the organization's costs in daily buckets. It requires an
+// admin key. It returns the raw JSON response.
+func (c *Client) ListCostsRaw(ctx context.Context, r UsageRequest) ([]byte, error) {
+	req, err := c.adminRequest(ctx, "/organization/costs?"+r.Query())
+	if err != nil {
+		return nil, fmt.Errorf("list costs: %w", err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list costs: %w", err)
+	}
+	return body, nil
+}
+
+// ListCosts lists the organization's costs in daily buckets. It returns a page of
+// time buckets, whether there are more, and the next page cursor.
+func (c *Client) ListCosts(ctx context.Context, r UsageRequest) ([]UsageBucket, bool, string, error) {
+	var page UsagePage
+	body, err := c.ListCostsRaw(ctx, r)
+	if err != nil {
+		return page.Data, page.HasMore, page.NextPage, err
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return page.Data, page.HasMore, page.NextPage, fmt.Errorf("list costs: unmarshal response: %w", err)
+	}
+	return page.Data, page.HasMore, page.NextPage, nil
+}
+
+// ListProjectsRaw lists the organization's projects. It requires an admin key.
+// It returns the raw JSON response.
+func (c *Client) ListProjectsRaw(ctx context.Context, limit int, after string) ([]byte, error) {
+	if limit < 1 {
+		limit = 100
+	}
+	path := fmt.Sprintf("/organization/projects?include_archived=true&limit=%d", limit)
+	if after != "" {
+		path += "&after=" + after
+	}
+	req, err := c.adminRequest(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("list projects: %w", err)
+	}
+	body, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("list projects: %w", err)
+	}
+	return body, nil
+}
+
+// ListProjects lists the organization's projects, including archived projects.
+func (c *Client) ListProjects(ctx context.Context, limit int, after string) ([]Project, bool, string, error) {
+	var list ProjectList
+	body, err := c.ListProjectsRaw(ctx, limit, after)
+	if err != nil {
+		return list.Data, list.HasMore, list.LastID, err
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return list.Data, list.HasMore, list.LastID, fmt.Errorf("list projects: unmarshal response: %w", err)
+	}
+	return list.Data, list.HasMore, list.LastID, nil
+}
@@ -0,0 +1,41 @@
+package openai
+
+// EmbeddingRequest represents a request structure for the embeddings API.
+type EmbeddingRequest struct {
+	// Model ID to use for embeddings. Example: "text-embedding-3-small" (required field)
+	Model string `json:"model"`
+
+	// Input is the text to embed: a string or a list of strings (required field).
+	Input any `json:"input"`
+
+	// Dimensions is the number of dimensions of the resulting embeddings.
+	// It's supported by the text-embedding-3 models.
+	Dimensions int `json:"dimensions,omitempty"`
+
+	// EncodingFormat is the format of the embeddings: "float" (default) or "base64".
+	EncodingFormat string `json:"encoding_format,omitempty"`
+
+	// User is a unique identifier representing your end-user.
+	User string `json:"user,omitempty"`
+}
+
+// EmbeddingResponse provides the embeddings of the input text(s).
+type EmbeddingResponse struct {
+	Object string         `json:"object"` // "list" is expected
+	Data   []Embedding    `json:"data"`   // one embedding per input
+	Model  string         `json:"model"`  // e.g. "text-embedding-3-small"
+	Usage  EmbeddingUsage `json:"usage"`
+}
+
+// Embedding is the vector representation of an input text.
+type Embedding struct {
+	Object    string    `json:"object"`    // "embedding" is expected
+	Index     int       `json:"index"`     // position of the input text
+	Embedding []float64 `json:"embedding"` // vector of floats
+}
+
+// EmbeddingUsage provides the number of tokens used by an embeddings request.
+type EmbeddingUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
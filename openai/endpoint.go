@@ -0,0 +1,17 @@
+package openai
+
+// API endpoints, as they're identified in batch requests and model capabilities.
+const (
+	EndpointChat        = "/v1/chat/completions"
+	EndpointResponses   = "/v1/responses"
+	EndpointEmbeddings  = "/v1/embeddings"
+	EndpointModerations = "/v1/moderations"
+	EndpointBatch       = "/v1/batch"
+	EndpointFineTuning  = "/v1/fine_tuning"
+)
+
+// BatchEndpoints lists the API endpoints that support batch requests.
+var BatchEndpoints = []string{EndpointChat, EndpointResponses, EndpointEmbeddings, EndpointModerations}
+
+// BatchCompletionWindows lists the completion windows accepted for batches.
+var BatchCompletionWindows = []string{"24h"}
@@ -48,3 +48,14 @@ func (e RequestError) Error() string {
 func (e RequestError) Unwrap() error {
 	return e.Err
 }
+
+// IsClientError returns true if the error is (or wraps) a RequestError with a 4xx
+// status code that retrying won't fix, e.g. an unknown ID (404) or an invalid
+// request (400). Request timeouts (408) and rate limits (429) are excluded.
+func IsClientError(err error) bool {
+	var e RequestError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Code >= 400 && e.Code < 500 && e.Code != 408 && e.Code != 429
+}
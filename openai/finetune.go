@@ -1,5 +1,30 @@
 package openai
 
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fine-tuning methods.
+const (
+	MethodSupervised    = "supervised"    // supervised fine-tuning (SFT) on example conversations
+	MethodDPO           = "dpo"           // direct preference optimization on preferred/non-preferred pairs
+	MethodReinforcement = "reinforcement" // reinforcement fine-tuning (RFT) with a grader
+)
+
+// FineTuneMethods lists the supported fine-tuning methods.
+var FineTuneMethods = []string{MethodSupervised, MethodDPO, MethodReinforcement}
+
 // FineTuneRecord provides a list of messages for fine-tuning a model.
 // Each role (system, user, assistant) should be represented in the message list.
 // Each record should be a persisted as a JSON object on a single line in a JSONL file.
@@ -8,6 +33,276 @@ type FineTuneRecord struct {
 	Messages []Message `json:"messages"`
 }
 
+// NewFineTuneRecord creates a FineTuneRecord of a single exchange: an optional
+// system message, a user prompt, and the target assistant completion.
+func NewFineTuneRecord(system, prompt, completion string) FineTuneRecord {
+	var messages []Message
+	if system != "" {
+		messages = append(messages, Message{Role: SYSTEM, Content: system})
+	}
+	return NewFineTuneRecordMessages(append(messages, Message{Role: USER, Content: prompt}), completion)
+}
+
+// NewFineTuneRecordMessages creates a FineTuneRecord of the messages of a (possibly
+// multi-turn) conversation, followed by the target assistant completion.
+func NewFineTuneRecordMessages(messages []Message, completion string) FineTuneRecord {
+	return FineTuneRecord{Messages: slices.Concat(messages, []Message{{Role: ASSISTANT, Content: completion}})}
+}
+
+// PreferenceRecord provides a pair of responses to an input conversation for DPO
+// fine-tuning: the preferred response, and the non-preferred response. Each record
+// should be persisted as a JSON object on a single line in a JSONL file.
+type PreferenceRecord struct {
+	Input              PreferenceInput `json:"input"`
+	PreferredOutput    []Message       `json:"preferred_output"`
+	NonPreferredOutput []Message       `json:"non_preferred_output"`
+}
+
+// PreferenceInput is the input conversation of a PreferenceRecord, typically
+// system and user messages.
+type PreferenceInput struct {
+	Messages []Message `json:"messages"`
+}
+
+// NewPreferenceRecord creates a PreferenceRecord of a single exchange: an optional
+// system message and a user prompt, with the preferred and non-preferred completions.
+func NewPreferenceRecord(system, prompt, preferred, nonPreferred string) PreferenceRecord {
+	var input []Message
+	if system != "" {
+		input = append(input, Message{Role: SYSTEM, Content: system})
+	}
+	input = append(input, Message{Role: USER, Content: prompt})
+	return NewPreferenceRecordMessages(input, preferred, nonPreferred)
+}
+
+// NewPreferenceRecordMessages creates a PreferenceRecord of the input messages of
+// a (possibly multi-turn) conversation, with the preferred and non-preferred
+// completions.
+func NewPreferenceRecordMessages(input []Message, preferred, nonPreferred string) PreferenceRecord {
+	return PreferenceRecord{
+		Input:              PreferenceInput{Messages: input},
+		PreferredOutput:    []Message{{Role: ASSISTANT, Content: preferred}},
+		NonPreferredOutput: []Message{{Role: ASSISTANT, Content: nonPreferred}},
+	}
+}
+
+// Validate checks the PreferenceRecord against the preference data format: input
+// messages with non-empty content that end with a user message, and preferred and
+// non-preferred outputs of a single non-empty assistant message, which must differ.
+func (r PreferenceRecord) Validate() error {
+	if len(r.Input.Messages) == 0 {
+		return fmt.Errorf("preference record: missing input messages")
+	}
+	for i, m := range r.Input.Messages {
+		if strings.TrimSpace(m.Content) == "" {
+			return fmt.Errorf("preference record: input message %d: empty %s content", i+1, m.Role)
+		}
+	}
+	if last := r.Input.Messages[len(r.Input.Messages)-1]; last.Role != USER {
+		return fmt.Errorf("preference record: last input message must be from the user, not %s", last.Role)
+	}
+	for name, output := range map[string][]Message{"preferred": r.PreferredOutput, "non-preferred": r.NonPreferredOutput} {
+		if len(output) != 1 || output[0].Role != ASSISTANT {
+			return fmt.Errorf("preference record: %s output must be a single assistant message", name)
+		}
+		if strings.TrimSpace(output[0].Content) == "" {
+			return fmt.Errorf("preference record: empty %s output", name)
+		}
+	}
+	if r.PreferredOutput[0].Content == r.NonPreferredOutput[0].Content {
+		return fmt.Errorf("preference record: preferred and non-preferred outputs are the same")
+	}
+	return nil
+}
+
+// MinFineTuneExamples is the minimum number of examples in a fine-tuning training file.
+const MinFineTuneExamples = 10
+
+// FineTuneDataError identifies a problem with a line of a fine-tuning data file.
+// Line 0 indicates a problem with the file as a whole.
+type FineTuneDataError struct {
+	Line    int    // line number, starting at 1
+	Message string // description of the problem
+}
+
+// Error provides a string representation of the FineTuneDataError.
+func (e FineTuneDataError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// FineTuneData summarizes a fine-tuning data file of FineTuneRecord (or
+// PreferenceRecord) lines, as validated by ValidateFineTuneData.
+type FineTuneData struct {
+	Examples int                 // number of examples
+	Tokens   []int               // estimated tokens per valid example
+	Errors   []FineTuneDataError // problems that would fail the job, in line order
+	Warnings []FineTuneDataError // problems that may degrade the model (e.g. duplicates)
+	lines    map[string]int      // example hashes, and the line where each first appears
+}
+
+// IsValid returns true if no errors were found in the fine-tuning data file.
+func (d FineTuneData) IsValid() bool {
+	return len(d.Errors) == 0
+}
+
+// TotalTokens returns the estimated number of tokens in the file's examples.
+func (d FineTuneData) TotalTokens() int {
+	total := 0
+	for _, n := range d.Tokens {
+		total += n
+	}
+	return total
+}
+
+// EstimateTokens estimates the number of tokens in the text, at about four
+// characters per token. It's an approximation of the model's tokenizer, which
+// is sufficient for checking limits and estimating costs.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// ValidateFineTuneData checks a JSONL file of FineTuneRecord lines before it's
+// uploaded, reporting every problem found: invalid JSON, unknown roles, invalid
+// role ordering (an optional system or developer message first, then alternating
+// user and assistant messages), missing assistant messages, empty content,
+// and examples that exceed the token limit (if positive). Lines of DPO preference
+// data are checked with PreferenceRecord.Validate instead. Duplicate examples are
+// reported as warnings. Training files also require MinFineTuneExamples.
+func ValidateFineTuneData(data []byte, maxTokens int) FineTuneData {
+	d := FineTuneData{lines: make(map[string]int)}
+	fail := func(line int, format string, args ...any) {
+		d.Errors = append(d.Errors, FineTuneDataError{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		n := i + 1
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		d.Examples++
+		var record FineTuneRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			fail(n, "invalid JSON: %v", err)
+			continue
+		}
+
+		// Validate a preference record, with input messages and a pair of outputs:
+		var preference PreferenceRecord
+		_ = json.Unmarshal(line, &preference)
+		if len(record.Messages) == 0 && preference.Input.Messages != nil {
+			if err := preference.Validate(); err != nil {
+				fail(n, "%v", err)
+				continue
+			}
+			tokens := 3
+			for _, m := range slices.Concat(preference.Input.Messages, preference.PreferredOutput,
+				preference.NonPreferredOutput) {
+				tokens += EstimateTokens(m.Content) + 3
+			}
+			if maxTokens > 0 && tokens > maxTokens {
+				fail(n, "too many tokens: about %d (maximum %d)", tokens, maxTokens)
+				continue
+			}
+			d.add(n, preference, tokens)
+			continue
+		}
+		if len(record.Messages) == 0 {
+			fail(n, "missing messages")
+			continue
+		}
+
+		// Validate the messages:
+		valid := true
+		assistant := false
+		tokens := 3 // every reply is primed with the assistant role
+		for j, m := range record.Messages {
+			prior := Role("")
+			if j > 0 {
+				prior = record.Messages[j-1].Role
+			}
+			switch {
+			case !m.Role.IsValid():
+				fail(n, "message %d: unknown role %q", j+1, m.Role)
+				valid = false
+			case m.Role.IsInstruction() && j > 0:
+				fail(n, "message %d: %s message must be first", j+1, m.Role)
+				valid = false
+			case m.Role == ASSISTANT && (prior == "" || prior.IsInstruction()):
+				fail(n, "message %d: assistant message must follow a user message", j+1)
+				valid = false
+			case m.Role == prior && j > 0:
+				fail(n, "message %d: consecutive %s messages", j+1, m.Role)
+				valid = false
+			}
+			if strings.TrimSpace(m.Content) == "" {
+				fail(n, "message %d: empty %s content", j+1, m.Role)
+				valid = false
+			}
+			assistant = assistant || m.Role == ASSISTANT
+			tokens += EstimateTokens(m.Content) + 3
+		}
+		if !assistant {
+			fail(n, "missing assistant message")
+			valid = false
+		}
+		if maxTokens > 0 && tokens > maxTokens {
+			fail(n, "too many tokens: about %d (maximum %d)", tokens, maxTokens)
+			valid = false
+		}
+		if valid {
+			d.add(n, record, tokens)
+		}
+	}
+	return d
+}
+
+// add records a valid example (a FineTuneRecord or PreferenceRecord) and its
+// estimated tokens, warning if it duplicates an earlier example.
+func (d *FineTuneData) add(line int, example any, tokens int) {
+	d.Tokens = append(d.Tokens, tokens)
+	canonical, _ := json.Marshal(example)
+	hash := sha256.Sum256(canonical)
+	key := hex.EncodeToString(hash[:])
+	if first, ok := d.lines[key]; ok {
+		d.Warnings = append(d.Warnings, FineTuneDataError{Line: line,
+			Message: fmt.Sprintf("duplicate example (first seen on line %d)", first)})
+	} else {
+		d.lines[key] = line
+	}
+}
+
+// FineTuneOverlap identifies the validation examples that also appear in the
+// training data. Overlapping examples make the validation metrics misleading.
+func FineTuneOverlap(train, valid FineTuneData) []FineTuneDataError {
+	var overlap []FineTuneDataError
+	for key, line := range valid.lines {
+		if first, ok := train.lines[key]; ok {
+			overlap = append(overlap, FineTuneDataError{Line: line,
+				Message: fmt.Sprintf("example also in training data (line %d)", first)})
+		}
+	}
+	slices.SortFunc(overlap, func(a, b FineTuneDataError) int { return a.Line - b.Line })
+	return overlap
+}
+
+// AutoEpochs returns the number of epochs that OpenAI chooses for the "auto"
+// setting: 3 epochs, adjusted so that training covers between 100 and 25,000
+// examples (with 1 to 25 epochs).
+func AutoEpochs(examples int) int {
+	switch {
+	case examples <= 0:
+		return 3
+	case 3*examples < 100:
+		return min(25, (100+examples-1)/examples)
+	case 3*examples > 25000:
+		return max(1, 25000/examples)
+	}
+	return 3
+}
+
 // FineTuneRequest is a request to fine-tune a model.
 type FineTuneRequest struct {
 	// TrainingFileID is the ID of an uploaded file containing the training data.
@@ -23,8 +318,22 @@ type FineTuneRequest struct {
 	// This can be useful for distinguishing between different fine-tuned models.
 	Suffix string `json:"suffix,omitempty"`
 
-	// HyperParameters provides optional hyperparameters for fine-tuning.
-	HyperParameters HyperParameters `json:"hyperparameters,omitempty"`
+	// HyperParameters provides optional hyperparameters for supervised fine-tuning.
+	// Deprecated: use the Method hyperparameters instead.
+	HyperParameters HyperParameters `json:"hyperparameters,omitzero"`
+
+	// Method is the fine-tuning method (supervised by default), with its hyperparameters.
+	Method *FineTuneMethod `json:"method,omitempty"`
+
+	// Seed controls the reproducibility of the job. If a seed isn't provided, one will be generated.
+	Seed int `json:"seed,omitempty"`
+
+	// Metadata is a map of up to 16 key-value pairs to include with the job.
+	// Keys may be up to 64 characters and values may be up to 512 characters.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Integrations is a list of integrations to enable for the job, e.g. Weights and Biases.
+	Integrations []FineTuneIntegration `json:"integrations,omitempty"`
 }
 
 // FineTuneJob provides information about an OpenAPI fine-tuning job.
@@ -57,6 +366,9 @@ type FineTuneJob struct {
 	// HyperParameters provides hyperparameters used for fine-tuning.
 	HyperParameters HyperParameters `json:"hyperparameters"`
 
+	// Method is the fine-tuning method used, with its hyperparameters.
+	Method FineTuneMethod `json:"method,omitzero"`
+
 	// OrganizationID is the ID of the organization that owns the fine-tune.
 	OrganizationID string `json:"organization_id,omitempty"`
 
@@ -70,9 +382,21 @@ type FineTuneJob struct {
 	TrainedTokens int `json:"trained_tokens"`
 
 	// Status is the current status of the fine-tuning job.
-	// Examples: validating_files, queued, running, succeeded, failed, cancelled
+	// Examples: validating_files, queued, running, paused, succeeded, failed, cancelled
 	Status string `json:"status"`
 
+	// Seed is the seed used for the fine-tuning job.
+	Seed int `json:"seed,omitempty"`
+
+	// EstimatedFinish is the estimated completion timestamp in epoch seconds, while the job is running.
+	EstimatedFinish int64 `json:"estimated_finish,omitempty"`
+
+	// Metadata is a map of key-value pairs provided when the job was created.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Integrations is a list of integrations enabled for the job.
+	Integrations []FineTuneIntegration `json:"integrations,omitempty"`
+
 	// Error provides information about an error that occurred during fine-tuning.
 	Error FineTuneError `json:"error,omitempty"`
 }
@@ -85,6 +409,144 @@ func (f FineTuneJob) Name() string {
 	return f.ID
 }
 
+// IsDone returns true if the fine-tuning job has succeeded, failed, or been cancelled.
+func (f FineTuneJob) IsDone() bool {
+	switch f.Status {
+	case "succeeded", "failed", "cancelled":
+		return true
+	}
+	return false
+}
+
+// FineTuneFilter selects fine-tuning jobs to list.
+type FineTuneFilter struct {
+	Metadata map[string]string // metadata key-value pairs that must all match (optional)
+	Limit    int               // number of jobs to retrieve (default 20)
+	After    string            // last job ID received, for pagination
+}
+
+// Query returns the URL-encoded query string for the FineTuneFilter.
+func (f FineTuneFilter) Query() string {
+	q := url.Values{}
+	for k, v := range f.Metadata {
+		q.Set("metadata["+k+"]", v)
+	}
+	limit := f.Limit
+	if limit < 1 {
+		limit = 20
+	}
+	q.Set("limit", strconv.Itoa(limit))
+	if f.After != "" {
+		q.Set("after", f.After)
+	}
+	return q.Encode()
+}
+
+// FineTuneIntegration is an integration enabled for a fine-tuning job.
+type FineTuneIntegration struct {
+	// Type is the integration type. Only "wandb" (Weights and Biases) is supported.
+	Type string `json:"type"`
+
+	// WandB provides the settings for the Weights and Biases integration.
+	WandB WandBIntegration `json:"wandb"`
+}
+
+// WandBIntegration provides the settings for reporting metrics to Weights and Biases.
+type WandBIntegration struct {
+	Project string   `json:"project"`          // project name for the run (required)
+	Name    string   `json:"name,omitempty"`   // display name for the run (default: job ID)
+	Entity  string   `json:"entity,omitempty"` // team or user name (default: API key owner)
+	Tags    []string `json:"tags,omitempty"`   // tags for the run
+}
+
+// FineTuneCheckpoint is a model checkpoint saved at the end of a fine-tuning epoch.
+// The checkpoint model can be used like any other fine-tuned model, e.g. for chat.
+type FineTuneCheckpoint struct {
+	// ID is the checkpoint ID, e.g. "ftckpt_qtZ5Gyk4BLq1SfLFWp3RtO3P".
+	ID string `json:"id"`
+
+	// Object is the object type, e.g. "fine_tuning.job.checkpoint".
+	Object string `json:"object"`
+
+	// CreatedAt is a creation timestamp in epoch seconds, e.g. 1669599635.
+	CreatedAt int64 `json:"created_at"`
+
+	// FineTunedModelCheckpoint is the model ID of the checkpoint,
+	// e.g. "ft:gpt-4o-mini-2024-07-18:org:essays:96olL566:ckpt-step-2000".
+	FineTunedModelCheckpoint string `json:"fine_tuned_model_checkpoint"`
+
+	// FineTuningJobID is the ID of the fine-tuning job that created the checkpoint.
+	FineTuningJobID string `json:"fine_tuning_job_id"`
+
+	// StepNumber is the training step at which the checkpoint was created.
+	StepNumber int `json:"step_number"`
+
+	// Metrics provides the training and validation metrics at the checkpoint.
+	Metrics FineTuneCheckpointMetrics `json:"metrics"`
+}
+
+// ValidationLoss returns the checkpoint's validation loss over the full validation
+// set, if available, or else over the last validation batch. It returns zero if
+// the job has no validation file.
+func (c FineTuneCheckpoint) ValidationLoss() float64 {
+	if c.Metrics.FullValidationLoss > 0 {
+		return c.Metrics.FullValidationLoss
+	}
+	return c.Metrics.ValidationLoss
+}
+
+// FineTuneCheckpointMetrics provides the metrics of a fine-tuning checkpoint.
+type FineTuneCheckpointMetrics struct {
+	Step                   float64 `json:"step,omitempty"`
+	TrainingLoss           float64 `json:"train_loss,omitempty"`
+	TrainingAccuracy       float64 `json:"train_mean_token_accuracy,omitempty"`
+	ValidationLoss         float64 `json:"valid_loss,omitempty"`
+	ValidationAccuracy     float64 `json:"valid_mean_token_accuracy,omitempty"`
+	FullValidationLoss     float64 `json:"full_valid_loss,omitempty"`
+	FullValidationAccuracy float64 `json:"full_valid_mean_token_accuracy,omitempty"`
+}
+
+// FineTuneCheckpoints provides a list of fine-tuning checkpoints.
+type FineTuneCheckpoints struct {
+	Object  string               `json:"object"`   // "list" is expected
+	Data    []FineTuneCheckpoint `json:"data"`     // list of checkpoints
+	FirstID string               `json:"first_id"` // first checkpoint ID in the collection
+	LastID  string               `json:"last_id"`  // use with the "after" query parameter
+	HasMore bool                 `json:"has_more"` // true if there are more checkpoints
+}
+
+// BestCheckpoint returns the checkpoint with the lowest validation loss, or the
+// latest checkpoint (by step) if none has a validation loss. Losses over the full
+// validation set are compared if any checkpoint has one (checkpoints without one
+// are then ignored), because the noisier batch losses aren't comparable with them.
+// It returns false if there are no checkpoints.
+func BestCheckpoint(checkpoints []FineTuneCheckpoint) (FineTuneCheckpoint, bool) {
+	var best FineTuneCheckpoint
+	if len(checkpoints) == 0 {
+		return best, false
+	}
+	full := slices.ContainsFunc(checkpoints, func(c FineTuneCheckpoint) bool {
+		return c.Metrics.FullValidationLoss > 0
+	})
+	loss := func(c FineTuneCheckpoint) float64 {
+		if full {
+			return c.Metrics.FullValidationLoss
+		}
+		return c.Metrics.ValidationLoss
+	}
+	best = checkpoints[0]
+	for _, c := range checkpoints[1:] {
+		cl, bl := loss(c), loss(best)
+		switch {
+		case cl > 0 && (bl == 0 || cl < bl):
+			best = c
+		case cl == 0 && bl == 0 && c.StepNumber > best.StepNumber:
+			best = c
+		}
+	}
+	return best, true
+}
+
 // FineTuneJobs provides a list of fine-tuning jobs.
 type FineTuneJobs struct {
 	Object  string        `json:"object"`   // "list" is expected
@@ -105,12 +567,135 @@ type FineTuneError struct {
 }
 
 // FineTuneMetric provides progress/performance metrics for a fine-tuning job.
+// Validation metrics are zero for steps without a validation evaluation.
 type FineTuneMetric struct {
-	Step               int     `json:"step,omitempty"`
-	TrainingLoss       float64 `json:"train_loss,omitempty"`
-	ValidationLoss     float64 `json:"valid_loss,omitempty"`
-	TrainingAccuracy   float64 `json:"train_mean_token_accuracy,omitempty"`
-	ValidationAccuracy float64 `json:"valid_mean_token_accuracy,omitempty"`
+	Step                   int     `json:"step,omitempty"`
+	TrainingLoss           float64 `json:"train_loss,omitempty"`
+	ValidationLoss         float64 `json:"valid_loss,omitempty"`
+	TrainingAccuracy       float64 `json:"train_mean_token_accuracy,omitempty"`
+	ValidationAccuracy     float64 `json:"valid_mean_token_accuracy,omitempty"`
+	FullValidationLoss     float64 `json:"full_valid_loss,omitempty"`
+	FullValidationAccuracy float64 `json:"full_valid_mean_token_accuracy,omitempty"`
+}
+
+// EvalLoss returns the step's validation loss over the full validation set, if
+// available, or else over the validation batch. It returns zero if the step
+// wasn't evaluated.
+func (m FineTuneMetric) EvalLoss() float64 {
+	if m.FullValidationLoss > 0 {
+		return m.FullValidationLoss
+	}
+	return m.ValidationLoss
+}
+
+// ParseFineTuneResults parses the contents of a fine-tuning job's result file: a
+// CSV file of step metrics, which OpenAI provides base64-encoded. Columns are
+// identified by name, and blank or unknown values are left as zero.
+func ParseFineTuneResults(data []byte) ([]FineTuneMetric, error) {
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("step")) {
+		decoded, err := base64.StdEncoding.DecodeString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("parse fine-tuning results: %w", err)
+		}
+		data = bytes.TrimSpace(decoded)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse fine-tuning results: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	header := rows[0]
+	metrics := make([]FineTuneMetric, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		var m FineTuneMetric
+		for j, value := range row {
+			if j >= len(header) || strings.TrimSpace(value) == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse fine-tuning results: row %d %s: %w", i+2, header[j], err)
+			}
+			switch strings.TrimSpace(header[j]) {
+			case "step":
+				m.Step = int(v)
+			case "train_loss":
+				m.TrainingLoss = v
+			case "train_accuracy", "train_mean_token_accuracy":
+				m.TrainingAccuracy = v
+			case "valid_loss":
+				m.ValidationLoss = v
+			case "valid_accuracy", "valid_mean_token_accuracy":
+				m.ValidationAccuracy = v
+			case "full_valid_loss":
+				m.FullValidationLoss = v
+			case "full_valid_accuracy", "full_valid_mean_token_accuracy":
+				m.FullValidationAccuracy = v
+			}
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// FineTuneSummary summarizes the learning curve of a fine-tuning job.
+type FineTuneSummary struct {
+	Steps             int     // number of training steps
+	TrainingLoss      float64 // final training loss, averaged over the last (up to) 10 steps
+	ValidationLoss    float64 // final validation loss
+	MinValidationLoss float64 // lowest validation loss
+	MinValidationStep int     // step with the lowest validation loss
+}
+
+// SummarizeFineTune summarizes the step metrics of a fine-tuning job. The
+// validation losses are over the full validation set if any step has one, or
+// else over the validation batches, so that like is compared with like.
+func SummarizeFineTune(metrics []FineTuneMetric) FineTuneSummary {
+	var s FineTuneSummary
+	tail := 0
+	for i := len(metrics) - 1; i >= 0 && tail < 10; i-- {
+		if metrics[i].TrainingLoss > 0 {
+			s.TrainingLoss += metrics[i].TrainingLoss
+			tail++
+		}
+	}
+	if tail > 0 {
+		s.TrainingLoss /= float64(tail)
+	}
+	full := slices.ContainsFunc(metrics, func(m FineTuneMetric) bool { return m.FullValidationLoss > 0 })
+	for _, m := range metrics {
+		s.Steps = max(s.Steps, m.Step)
+		loss := m.ValidationLoss
+		if full {
+			loss = m.FullValidationLoss
+		}
+		if loss == 0 {
+			continue
+		}
+		s.ValidationLoss = loss
+		if s.MinValidationLoss == 0 || loss < s.MinValidationLoss {
+			s.MinValidationLoss, s.MinValidationStep = loss, m.Step
+		}
+	}
+	return s
+}
+
+// Gap returns the difference between the final validation and training losses.
+// A large gap suggests that the model is memorizing the training data.
+func (s FineTuneSummary) Gap() float64 {
+	if s.ValidationLoss == 0 {
+		return 0
+	}
+	return s.ValidationLoss - s.TrainingLoss
+}
+
+// IsOverfit returns true if the final validation loss has risen more than 10%
+// above its minimum, suggesting that training continued past the best step.
+func (s FineTuneSummary) IsOverfit() bool {
+	return s.MinValidationLoss > 0 && s.ValidationLoss > 1.1*s.MinValidationLoss
 }
 
 // FineTuneEvent provides information about an OpenAPI fine-tuning event.
@@ -144,18 +729,205 @@ type FineTuneEvents struct {
 	HasMore bool            `json:"has_more"` // true if there are more events
 }
 
-// HyperParameters provides hyperparameters for fine-tuning.
+// FineTuneEventsSince returns the events of a fine-tuning job that follow the last
+// event seen (or all events, if lastEventID is empty), oldest first. Events are
+// listed newest first, so pages are read with the list function (e.g. a call to
+// Client.ListFineTuneEvents) using the "after" cursor, until the last event seen
+// is reached.
+func FineTuneEventsSince(lastEventID string, list func(after string) ([]FineTuneEvent, bool, error)) ([]FineTuneEvent, error) {
+	var events []FineTuneEvent
+	after := ""
+	for {
+		page, hasMore, err := list(after)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range page {
+			if e.ID == lastEventID {
+				hasMore = false
+				break
+			}
+			events = append(events, e)
+		}
+		if !hasMore || len(page) == 0 {
+			break
+		}
+		after = page[len(page)-1].ID
+	}
+	slices.Reverse(events)
+	return events, nil
+}
+
+// FineTuneMethod is a fine-tuning method, with its hyperparameters.
+type FineTuneMethod struct {
+	// Type is the fine-tuning method: "supervised", "dpo", or "reinforcement".
+	Type string `json:"type"`
+
+	// Supervised provides the settings for supervised fine-tuning.
+	Supervised *SupervisedMethod `json:"supervised,omitempty"`
+
+	// DPO provides the settings for direct preference optimization.
+	DPO *DPOMethod `json:"dpo,omitempty"`
+
+	// Reinforcement provides the settings for reinforcement fine-tuning.
+	Reinforcement *ReinforcementMethod `json:"reinforcement,omitempty"`
+}
+
+// SupervisedMethod provides the settings for supervised fine-tuning.
+type SupervisedMethod struct {
+	HyperParameters HyperParameters `json:"hyperparameters,omitzero"`
+}
+
+// DPOMethod provides the settings for direct preference optimization.
+type DPOMethod struct {
+	HyperParameters HyperParameters `json:"hyperparameters,omitzero"`
+}
+
+// ReinforcementMethod provides the settings for reinforcement fine-tuning.
+type ReinforcementMethod struct {
+	// Grader is the grader configuration (JSON) used to score the model's responses.
+	Grader json.RawMessage `json:"grader"`
+
+	HyperParameters HyperParameters `json:"hyperparameters,omitzero"`
+}
+
+// NewFineTuneMethod creates a FineTuneMethod of the specified type with the
+// hyperparameters. A reinforcement method requires a grader configuration.
+func NewFineTuneMethod(methodType string, hp HyperParameters, grader json.RawMessage) (FineTuneMethod, error) {
+	m := FineTuneMethod{Type: methodType}
+	switch methodType {
+	case MethodSupervised:
+		m.Supervised = &SupervisedMethod{HyperParameters: hp}
+	case MethodDPO:
+		m.DPO = &DPOMethod{HyperParameters: hp}
+	case MethodReinforcement:
+		if len(grader) == 0 {
+			return m, fmt.Errorf("fine-tuning method %s: grader required", methodType)
+		}
+		m.Reinforcement = &ReinforcementMethod{Grader: grader, HyperParameters: hp}
+	default:
+		return m, fmt.Errorf("invalid fine-tuning method %s: expecting supervised, dpo, or reinforcement", methodType)
+	}
+	if !hp.Beta.IsZero() && methodType != MethodDPO {
+		return m, fmt.Errorf("fine-tuning method %s: beta is only supported by dpo", methodType)
+	}
+	return m, nil
+}
+
+// HyperParameters returns the hyperparameters of the method.
+func (m FineTuneMethod) HyperParameters() HyperParameters {
+	switch {
+	case m.Supervised != nil:
+		return m.Supervised.HyperParameters
+	case m.DPO != nil:
+		return m.DPO.HyperParameters
+	case m.Reinforcement != nil:
+		return m.Reinforcement.HyperParameters
+	}
+	return HyperParameters{}
+}
+
+// HyperParameters provides hyperparameters for fine-tuning. Each one may be
+// "auto" (chosen by OpenAI based on the training data), or a specific value.
+// Unspecified (zero) values are omitted, and default to "auto".
 type HyperParameters struct {
-	// EpochCount is the number of epochs to train for. The default is 4.
+	// EpochCount is the number of epochs to train for.
 	// An epoch refers to one full cycle through the training dataset.
-	EpochCount int `json:"n_epochs,omitempty"`
+	EpochCount Auto[int] `json:"n_epochs,omitzero"`
 
 	// BatchSize is the number of training examples to process in parallel.
 	// By default, the batch size will be dynamically configured to be ~0.2%
 	// of the number of examples in the training set, capped at 256.
-	BatchSize int `json:"batch_size,omitempty"`
+	BatchSize Auto[int] `json:"batch_size,omitzero"`
 
 	// LearningRate is the learning rate multiplier for the fine-tuning.
 	// A smaller learning rate may be useful to avoid overfitting.
-	LearningRate float64 `json:"learning_rate_multiplier,omitempty"`
+	LearningRate Auto[float64] `json:"learning_rate_multiplier,omitzero"`
+
+	// Beta weights the penalty between the policy and reference model (DPO only).
+	// A higher beta keeps the fine-tuned model closer to the base model.
+	Beta Auto[float64] `json:"beta,omitzero"`
+
+	// ReasoningEffort is the reasoning effort for reinforcement fine-tuning.
+	ReasoningEffort ReasoningEffort `json:"reasoning_effort,omitempty"`
+
+	// ComputeMultiplier scales the compute used for exploration (reinforcement only).
+	ComputeMultiplier Auto[float64] `json:"compute_multiplier,omitzero"`
+
+	// EvalInterval is the number of training steps between evaluations (reinforcement only).
+	EvalInterval Auto[int] `json:"eval_interval,omitzero"`
+
+	// EvalSamples is the number of samples per evaluation (reinforcement only).
+	EvalSamples Auto[int] `json:"eval_samples,omitzero"`
+}
+
+// Auto is a hyperparameter value that is either "auto" or a specific number.
+// Its zero value is unspecified.
+type Auto[T int | float64] struct {
+	Value T    // specific value, if not auto
+	Auto  bool // "auto"?
+}
+
+// AutoValue returns an Auto value of "auto".
+func AutoValue[T int | float64]() Auto[T] {
+	return Auto[T]{Auto: true}
+}
+
+// Value returns an Auto value with the specified number.
+func Value[T int | float64](v T) Auto[T] {
+	return Auto[T]{Value: v}
+}
+
+// ParseAuto parses an Auto value: "auto", a number, or "" (unspecified).
+func ParseAuto[T int | float64](s string) (Auto[T], error) {
+	var a Auto[T]
+	if s == "" {
+		return a, nil
+	}
+	if err := json.Unmarshal([]byte(strconv.Quote(s)), &a); err != nil {
+		return a, fmt.Errorf("invalid value %q: expecting auto or a number", s)
+	}
+	return a, nil
+}
+
+// IsZero returns true if the value is unspecified.
+func (a Auto[T]) IsZero() bool {
+	return !a.Auto && a.Value == 0
+}
+
+// String returns "auto", the number, or "" if unspecified.
+func (a Auto[T]) String() string {
+	switch {
+	case a.Auto:
+		return "auto"
+	case a.IsZero():
+		return ""
+	}
+	return fmt.Sprint(a.Value)
+}
+
+// MarshalJSON encodes the value as "auto" or a number.
+func (a Auto[T]) MarshalJSON() ([]byte, error) {
+	if a.Auto {
+		return []byte(`"auto"`), nil
+	}
+	return json.Marshal(a.Value)
+}
+
+// UnmarshalJSON decodes "auto", a number, or a numeric string.
+func (a *Auto[T]) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "auto" {
+			*a = Auto[T]{Auto: true}
+			return nil
+		}
+		data = []byte(s)
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid hyperparameter %s: expecting auto or a number", string(data))
+	}
+	*a = Auto[T]{Value: v}
+	return nil
 }
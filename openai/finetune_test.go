@@ -0,0 +1,269 @@
+package openai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestBestCheckpoint(t *testing.T) {
+	expect := assert.New(t)
+	_, ok := BestCheckpoint(nil)
+	expect.False(ok, "No checkpoints")
+
+	checkpoints := []FineTuneCheckpoint{
+		{ID: "c3", StepNumber: 300, Metrics: FineTuneCheckpointMetrics{ValidationLoss: 0.40, FullValidationLoss: 0.35}},
+		{ID: "c2", StepNumber: 200, Metrics: FineTuneCheckpointMetrics{ValidationLoss: 0.30}},
+		{ID: "c1", StepNumber: 100, Metrics: FineTuneCheckpointMetrics{ValidationLoss: 0.50, FullValidationLoss: 0.45}},
+	}
+	best, ok := BestCheckpoint(checkpoints)
+	if expect.True(ok) {
+		expect.Equal("c3", best.ID, "Lowest full validation loss")
+	}
+	expect.Equal(0.35, checkpoints[0].ValidationLoss(), "Full validation loss preferred")
+	for i := range checkpoints {
+		checkpoints[i].Metrics.FullValidationLoss = 0
+	}
+	best, _ = BestCheckpoint(checkpoints)
+	expect.Equal("c2", best.ID, "Lowest batch validation loss")
+
+	checkpoints = []FineTuneCheckpoint{{ID: "c1", StepNumber: 100}, {ID: "c3", StepNumber: 300}, {ID: "c2", StepNumber: 200}}
+	best, _ = BestCheckpoint(checkpoints)
+	expect.Equal("c3", best.ID, "Latest checkpoint without validation")
+}
+
+func TestFineTuneFilter(t *testing.T) {
+	expect := assert.New(t)
+	expect.Equal("limit=20", FineTuneFilter{}.Query())
+	f := FineTuneFilter{Metadata: map[string]string{"project": "essays"}, Limit: 5, After: "ftjob-1"}
+	expect.Equal("after=ftjob-1&limit=5&metadata%5Bproject%5D=essays", f.Query())
+}
+
+func TestAuto(t *testing.T) {
+	expect := assert.New(t)
+	hp := HyperParameters{EpochCount: Value(3), BatchSize: AutoValue[int](), LearningRate: Value(0.5)}
+	b, err := json.Marshal(hp)
+	if expect.NoError(err) {
+		expect.JSONEq(`{"n_epochs":3,"batch_size":"auto","learning_rate_multiplier":0.5}`, string(b), "Zero values omitted")
+	}
+	var decoded HyperParameters
+	if expect.NoError(json.Unmarshal([]byte(`{"n_epochs":"auto","batch_size":8,"beta":"0.1"}`), &decoded)) {
+		expect.True(decoded.EpochCount.Auto)
+		expect.Equal(8, decoded.BatchSize.Value)
+		expect.Equal(0.1, decoded.Beta.Value, "Numeric string")
+		expect.Equal("auto", decoded.EpochCount.String())
+		expect.Equal("", decoded.LearningRate.String())
+	}
+	expect.Error(json.Unmarshal([]byte(`{"n_epochs":"many"}`), &decoded))
+
+	epochs, err := ParseAuto[int]("auto")
+	expect.NoError(err)
+	expect.Equal(AutoValue[int](), epochs)
+	lr, err := ParseAuto[float64]("1.8")
+	expect.NoError(err)
+	expect.Equal(Value(1.8), lr)
+	blank, err := ParseAuto[int]("")
+	expect.NoError(err)
+	expect.True(blank.IsZero())
+	_, err = ParseAuto[int]("1.5")
+	expect.Error(err, "Not an integer")
+}
+
+func TestFineTuneMethod(t *testing.T) {
+	expect := assert.New(t)
+	m, err := NewFineTuneMethod(MethodDPO, HyperParameters{Beta: Value(0.2), EpochCount: AutoValue[int]()}, nil)
+	if expect.NoError(err) {
+		b, _ := json.Marshal(FineTuneRequest{TrainingFileID: "file-1", Model: "gpt-4.1-mini", Method: &m})
+		expect.JSONEq(`{"training_file":"file-1","model":"gpt-4.1-mini",
+			"method":{"type":"dpo","dpo":{"hyperparameters":{"n_epochs":"auto","beta":0.2}}}}`, string(b))
+		expect.Equal(0.2, m.HyperParameters().Beta.Value)
+	}
+	_, err = NewFineTuneMethod(MethodSupervised, HyperParameters{Beta: Value(0.2)}, nil)
+	expect.Error(err, "Beta is DPO only")
+	_, err = NewFineTuneMethod(MethodReinforcement, HyperParameters{}, nil)
+	expect.Error(err, "Grader required")
+	_, err = NewFineTuneMethod("unsupervised", HyperParameters{}, nil)
+	expect.Error(err, "Invalid method")
+}
+
+func TestParseFineTuneResults(t *testing.T) {
+	expect := assert.New(t)
+	results := "step,train_loss,train_accuracy,valid_loss,valid_mean_token_accuracy\n" +
+		"1,2.0,0.5,,\n2,1.0,0.6,1.2,0.55\n3,0.8,0.7,,\n4,0.6,0.8,1.4,0.5\n"
+	encoded := base64.StdEncoding.EncodeToString([]byte(results))
+	for _, data := range []string{results, encoded} {
+		metrics, err := ParseFineTuneResults([]byte(data))
+		if expect.NoError(err) && expect.Len(metrics, 4) {
+			expect.Equal(2, metrics[1].Step)
+			expect.Equal(0.6, metrics[1].TrainingAccuracy)
+			expect.Equal(1.2, metrics[1].EvalLoss())
+			expect.Zero(metrics[2].EvalLoss(), "Not evaluated")
+		}
+	}
+	_, err := ParseFineTuneResults([]byte("step,train_loss\n1,abc\n"))
+	expect.Error(err, "Invalid value")
+
+	metrics, _ := ParseFineTuneResults([]byte(results))
+	s := SummarizeFineTune(metrics)
+	expect.Equal(4, s.Steps)
+	expect.InDelta(1.1, s.TrainingLoss, 1e-9, "Mean of last 10 steps")
+	expect.Equal(1.4, s.ValidationLoss)
+	expect.Equal(1.2, s.MinValidationLoss)
+	expect.Equal(2, s.MinValidationStep)
+	expect.InDelta(0.3, s.Gap(), 1e-9)
+	expect.True(s.IsOverfit())
+
+	// Noisy batch losses aren't compared with full validation losses:
+	metrics, _ = ParseFineTuneResults([]byte("step,train_loss,valid_loss,full_valid_loss\n" +
+		"1,2.0,0.9,\n2,1.0,1.1,1.0\n3,0.8,1.3,\n4,0.6,1.2,1.02\n"))
+	s = SummarizeFineTune(metrics)
+	expect.Equal(1.02, s.ValidationLoss)
+	expect.Equal(1.0, s.MinValidationLoss)
+	expect.Equal(2, s.MinValidationStep)
+	expect.False(s.IsOverfit())
+}
+
+func TestValidateFineTuneData(t *testing.T) {
+	expect := assert.New(t)
+	example := `{"messages":[{"role":"system","content":"Score it."},{"role":"user","content":"Essay %d"},{"role":"assistant","content":"3"}]}`
+	var lines []string
+	for i := range 10 {
+		lines = append(lines, fmt.Sprintf(example, i))
+	}
+	train := ValidateFineTuneData([]byte(strings.Join(lines, "\n")), 0)
+	expect.True(train.IsValid(), train.Errors)
+	expect.Equal(10, train.Examples)
+	expect.Len(train.Tokens, 10)
+	expect.Equal(10*train.Tokens[0], train.TotalTokens())
+
+	bad := []string{
+		`{"messages":[`,
+		`{"messages":[{"role":"user","content":"Essay"}]}`,
+		`{"messages":[{"role":"user","content":"Essay"},{"role":"system","content":"Score it."},{"role":"assistant","content":"3"}]}`,
+		`{"messages":[{"role":"system","content":"Score it."},{"role":"assistant","content":"3"}]}`,
+		`{"messages":[{"role":"user","content":"Essay"},{"role":"assistant","content":" "}]}`,
+		`{"messages":[{"role":"user","content":"Essay"},{"role":"critic","content":"3"}]}`,
+		`{"messages":[{"role":"user","content":"Essay"},{"role":"assistant","content":"Long answer"}]}`,
+		fmt.Sprintf(example, 1),
+	}
+	d := ValidateFineTuneData([]byte(strings.Join(bad, "\n")), 10)
+	expect.False(d.IsValid())
+	expect.Equal(8, d.Examples)
+	expect.Len(d.Warnings, 0)
+	failed := make(map[int]bool)
+	for _, e := range d.Errors {
+		failed[e.Line] = true
+	}
+	for n := 1; n <= 7; n++ {
+		expect.True(failed[n], "Error on line %d", n)
+	}
+
+	valid := ValidateFineTuneData([]byte(fmt.Sprintf(example, 3)+"\n"+fmt.Sprintf(example, 3)), 0)
+	expect.Len(valid.Warnings, 1, "Duplicate")
+	overlap := FineTuneOverlap(train, valid)
+	if expect.Len(overlap, 1) {
+		expect.Equal("line 1: example also in training data (line 4)", overlap[0].Error())
+	}
+
+	expect.Equal(3, AutoEpochs(1000))
+	expect.Equal(10, AutoEpochs(10))
+	expect.Equal(2, AutoEpochs(10000))
+}
+
+func TestFineTuneRecordMessages(t *testing.T) {
+	expect := assert.New(t)
+	input := []Message{{Role: USER, Content: "Essay 1"}, {Role: ASSISTANT, Content: "3"}, {Role: USER, Content: "Essay 2"}}
+	r := NewFineTuneRecordMessages(input, "4")
+	if expect.Len(r.Messages, 4) {
+		expect.Equal(Message{Role: ASSISTANT, Content: "4"}, r.Messages[3])
+	}
+	expect.Len(input, 3, "Input unchanged")
+}
+
+func TestPreferenceRecord(t *testing.T) {
+	expect := assert.New(t)
+	r := NewPreferenceRecord("Explain the score.", "Essay", "Clear rationale. 3", "Vague. 3")
+	expect.NoError(r.Validate())
+	j, err := json.Marshal(r)
+	if expect.NoError(err) {
+		expect.Contains(string(j), `"input":{"messages":[{"role":"system"`)
+		expect.Contains(string(j), `"preferred_output":[{"role":"assistant","content":"Clear rationale. 3"}]`)
+		expect.Contains(string(j), `"non_preferred_output":[{"role":"assistant","content":"Vague. 3"}]`)
+	}
+	expect.ErrorContains(NewPreferenceRecord("", "Essay", "Same", "Same").Validate(), "the same")
+	expect.ErrorContains(NewPreferenceRecord("", "Essay", "Good", " ").Validate(), "empty non-preferred")
+	expect.ErrorContains(NewPreferenceRecord("", " ", "Good", "Bad").Validate(), "empty user")
+	r.Input.Messages = r.Input.Messages[:1]
+	expect.ErrorContains(r.Validate(), "last input message")
+	input := []Message{{Role: USER, Content: "Essay 1"}, {Role: ASSISTANT, Content: "3"}, {Role: USER, Content: "Essay 2"}}
+	expect.NoError(NewPreferenceRecordMessages(input, "4", "1").Validate(), "Few-shot input")
+
+	// Validate a file of preference records:
+	var lines []string
+	for i := range 3 {
+		b, _ := json.Marshal(NewPreferenceRecord("", fmt.Sprintf("Essay %d", i), "Clear rationale. 3", "Vague. 3"))
+		lines = append(lines, string(b))
+	}
+	b, _ := json.Marshal(NewPreferenceRecord("", "Essay 3", "Same", "Same"))
+	lines = append(lines, lines[0], string(b))
+	d := ValidateFineTuneData([]byte(strings.Join(lines, "\n")), 0)
+	expect.Equal(5, d.Examples)
+	expect.Len(d.Tokens, 4)
+	if expect.Len(d.Errors, 1) {
+		expect.Equal(5, d.Errors[0].Line)
+		expect.Contains(d.Errors[0].Message, "the same")
+	}
+	if expect.Len(d.Warnings, 1) {
+		expect.Equal("line 4: duplicate example (first seen on line 1)", d.Warnings[0].Error())
+	}
+}
+
+func TestFineTuneEventsSince(t *testing.T) {
+	expect := assert.New(t)
+	// Five events, listed newest first in pages of two:
+	all := []FineTuneEvent{{ID: "e5"}, {ID: "e4"}, {ID: "e3"}, {ID: "e2"}, {ID: "e1"}}
+	calls := 0
+	list := func(after string) ([]FineTuneEvent, bool, error) {
+		calls++
+		i := 0
+		for after != "" && all[i].ID != after {
+			i++
+		}
+		if after != "" {
+			i++
+		}
+		end := min(i+2, len(all))
+		return all[i:end], end < len(all), nil
+	}
+	ids := func(events []FineTuneEvent) []string {
+		var ids []string
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+	events, err := FineTuneEventsSince("", list)
+	if expect.NoError(err) {
+		expect.Equal([]string{"e1", "e2", "e3", "e4", "e5"}, ids(events), "Oldest first")
+		expect.Equal(3, calls)
+	}
+	calls = 0
+	events, err = FineTuneEventsSince("e4", list)
+	if expect.NoError(err) {
+		expect.Equal([]string{"e5"}, ids(events), "New events only")
+		expect.Equal(1, calls, "Stops at the last event seen")
+	}
+	events, err = FineTuneEventsSince("e5", list)
+	expect.NoError(err)
+	expect.Empty(events)
+	_, err = FineTuneEventsSince("", func(after string) ([]FineTuneEvent, bool, error) {
+		return nil, false, RequestError{Code: 404}
+	})
+	expect.Error(err)
+	expect.True(IsClientError(fmt.Errorf("read fine-tuning job: %w", err)), "Unknown job")
+	expect.False(IsClientError(RequestError{Code: 429}), "Rate limited")
+	expect.False(IsClientError(RequestError{Code: 503}), "Server error")
+}
@@ -1,13 +1,6 @@
 package openai
 
-// CommonModels is a collection of commonly-used OpenAI models.
-var CommonModels = map[string]bool{
-	"gpt-3.5-turbo":     true,
-	"gpt-3.5-turbo-16k": true,
-	"gpt-4":             true,
-	"gpt-4-turbo":       true,
-	"gpt-4o":            true,
-}
+import "strings"
 
 // Model identifies an OpenAPI model.
 type Model struct {
@@ -28,3 +21,47 @@ type ModelList struct {
 	Object string  `json:"object"` // "list" is expected
 	Data   []Model `json:"data"`   // list of models
 }
+
+// ModelCapabilities describes the chat request parameters supported by a model.
+type ModelCapabilities struct {
+	// Reasoning models (e.g. o3, gpt-5) use max_completion_tokens instead of
+	// max_tokens, accept a reasoning_effort, prefer developer messages over
+	// system messages, and reject sampling parameters such as temperature.
+	Reasoning bool `json:"reasoning,omitempty"`
+
+	// Verbosity indicates that the model accepts a verbosity parameter.
+	Verbosity bool `json:"verbosity,omitempty"`
+}
+
+// Capabilities returns the capabilities of the specified model ID from the model
+// Registry, or infers them from its model family if it's not in the catalog.
+// Fine-tuned models (e.g. "ft:o4-mini-2025-04-16:org::id") share the
+// capabilities of their base model.
+func Capabilities(id string) ModelCapabilities {
+	if m, ok := Registry.Lookup(id); ok {
+		return ModelCapabilities{Reasoning: m.Reasoning, Verbosity: m.Verbosity}
+	}
+	id = BaseModelID(id)
+	switch {
+	case strings.HasPrefix(id, "gpt-5-chat"):
+		return ModelCapabilities{}
+	case strings.HasPrefix(id, "gpt-5"):
+		return ModelCapabilities{Reasoning: true, Verbosity: true}
+	case strings.HasPrefix(id, "o1"), strings.HasPrefix(id, "o3"), strings.HasPrefix(id, "o4"):
+		return ModelCapabilities{Reasoning: true}
+	}
+	return ModelCapabilities{}
+}
+
+// BaseModelID returns the base model ID of a fine-tuned model ID, or the
+// provided ID if it's not a fine-tuned model. Example: the base model ID of
+// "ft:gpt-4o-mini-2024-07-18:org:suffix:id" is "gpt-4o-mini-2024-07-18".
+func BaseModelID(id string) string {
+	if strings.HasPrefix(id, "ft:") {
+		parts := strings.Split(id, ":")
+		if len(parts) > 1 {
+			return parts[1]
+		}
+	}
+	return id
+}
@@ -0,0 +1,29 @@
+package openai
+
+// ModerationRequest represents a request structure for the moderations API.
+type ModerationRequest struct {
+	// Model ID to use for moderation. Example: "omni-moderation-latest"
+	Model string `json:"model,omitempty"`
+
+	// Input is the text to classify: a string or a list of strings (required field).
+	Input any `json:"input"`
+}
+
+// ModerationResponse provides the moderation results for the input text(s).
+type ModerationResponse struct {
+	ID      string             `json:"id"`      // e.g. "modr-AB8CjOTu2jiq12hp1AQPfeqFWaORR"
+	Model   string             `json:"model"`   // e.g. "omni-moderation-latest"
+	Results []ModerationResult `json:"results"` // one result per input
+}
+
+// ModerationResult classifies an input text as potentially harmful.
+type ModerationResult struct {
+	// Flagged is true if the input violates the usage policies in any category.
+	Flagged bool `json:"flagged"`
+
+	// Categories identifies the flagged categories, e.g. "harassment".
+	Categories map[string]bool `json:"categories"`
+
+	// CategoryScores provides the model's confidence in each category, between 0 and 1.
+	CategoryScores map[string]float64 `json:"category_scores"`
+}
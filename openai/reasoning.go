@@ -0,0 +1,68 @@
+package openai
+
+// ReasoningEffort constrains the effort a reasoning model spends on reasoning
+// before it responds. Reducing the effort results in faster responses and fewer
+// reasoning tokens. The default (empty) value leaves the choice to the model.
+type ReasoningEffort string
+
+const (
+	MinimalEffort ReasoningEffort = "minimal"
+	LowEffort     ReasoningEffort = "low"
+	MediumEffort  ReasoningEffort = "medium"
+	HighEffort    ReasoningEffort = "high"
+)
+
+// ReasoningEfforts is a list of all valid ReasoningEfforts.
+var ReasoningEfforts = []ReasoningEffort{MinimalEffort, LowEffort, MediumEffort, HighEffort}
+
+// String returns the string representation of a ReasoningEffort.
+func (e ReasoningEffort) String() string {
+	return string(e)
+}
+
+// IsValid returns true if the ReasoningEffort is valid. An empty value is valid,
+// indicating that the model default should be used.
+func (e ReasoningEffort) IsValid() bool {
+	if e == "" {
+		return true
+	}
+	for _, effort := range ReasoningEfforts {
+		if effort == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Verbosity constrains the length of a model's response. Lower values result in
+// more concise responses, while higher values result in more verbose responses.
+// The default (empty) value leaves the choice to the model.
+type Verbosity string
+
+const (
+	LowVerbosity    Verbosity = "low"
+	MediumVerbosity Verbosity = "medium"
+	HighVerbosity   Verbosity = "high"
+)
+
+// Verbosities is a list of all valid Verbosities.
+var Verbosities = []Verbosity{LowVerbosity, MediumVerbosity, HighVerbosity}
+
+// String returns the string representation of a Verbosity.
+func (v Verbosity) String() string {
+	return string(v)
+}
+
+// IsValid returns true if the Verbosity is valid. An empty value is valid,
+// indicating that the model default should be used.
+func (v Verbosity) IsValid() bool {
+	if v == "" {
+		return true
+	}
+	for _, verbosity := range Verbosities {
+		if verbosity == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package openai
+
+import "strings"
+
+// ResponseRequest represents a request structure for the responses API. This
+// implementation is focused on text responses; tools are not provided here.
+type ResponseRequest struct {
+	// Model ID to use for the response. Example: "gpt-5" (required field)
+	Model string `json:"model"`
+
+	// Input is the text input: a string, or a list of Message inputs (required field).
+	Input any `json:"input"`
+
+	// Instructions is a system (or developer) message for the model.
+	Instructions string `json:"instructions,omitempty"`
+
+	// MaxOutputTokens is an upper bound for the number of tokens that can be
+	// generated, including visible output tokens and reasoning tokens.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+
+	// Temperature is the sampling temperature, between 0 and 2 (not for reasoning models).
+	Temperature float32 `json:"temperature,omitempty"`
+
+	// Reasoning provides options for reasoning models.
+	Reasoning *ResponseReasoning `json:"reasoning,omitempty"`
+
+	// Text provides options for the text output.
+	Text *ResponseText `json:"text,omitempty"`
+
+	// Store indicates whether OpenAI should store the response. The default is true.
+	Store *bool `json:"store,omitempty"`
+
+	// Metadata is a map of up to 16 key-value pairs to include with the response.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ResponseReasoning provides options for reasoning models.
+type ResponseReasoning struct {
+	Effort ReasoningEffort `json:"effort,omitempty"`
+}
+
+// ResponseText provides options for the text output of a response.
+type ResponseText struct {
+	Verbosity Verbosity `json:"verbosity,omitempty"`
+}
+
+// Response is a model response from the responses API.
+type Response struct {
+	ID        string            `json:"id"`                 // e.g. "resp_67ccd2bed1ec8190b14f964abc054267"
+	Object    string            `json:"object"`             // "response" is expected
+	CreatedAt int64             `json:"created_at"`         // epoch seconds
+	Status    string            `json:"status"`             // e.g. "completed" or "incomplete"
+	Model     string            `json:"model"`              // e.g. "gpt-5-2025-08-07"
+	Output    []ResponseOutput  `json:"output"`             // output items (messages and reasoning)
+	Usage     ResponseUsage     `json:"usage"`              // token usage
+	Error     *APIError         `json:"error,omitempty"`    // error, if the response failed
+	Metadata  map[string]string `json:"metadata,omitempty"` // metadata from the request
+}
+
+// OutputText returns the text content of the response's output messages.
+func (r Response) OutputText() string {
+	var parts []string
+	for _, o := range r.Output {
+		if o.Type != "message" {
+			continue
+		}
+		for _, c := range o.Content {
+			if c.Type == "output_text" {
+				parts = append(parts, c.Text)
+			}
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// ResponseOutput is an output item of a response, e.g. a "message" or "reasoning".
+type ResponseOutput struct {
+	Type    string            `json:"type"`
+	ID      string            `json:"id"`
+	Role    Role              `json:"role,omitempty"`
+	Status  string            `json:"status,omitempty"`
+	Content []ResponseContent `json:"content,omitempty"`
+}
+
+// ResponseContent is a content part of a response output message.
+type ResponseContent struct {
+	Type string `json:"type"` // e.g. "output_text" or "refusal"
+	Text string `json:"text,omitempty"`
+}
+
+// ResponseUsage provides the number of tokens used by a response.
+type ResponseUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
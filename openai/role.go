@@ -8,6 +8,11 @@ type Role string
 // It's the first message in a conversation.
 const SYSTEM Role = "system"
 
+// A DEVELOPER message replaces the SYSTEM message for reasoning models
+// (e.g. o3, gpt-5). It provides instructions that the model should follow,
+// regardless of the messages sent by the user.
+const DEVELOPER Role = "developer"
+
 // USER messages instruct the ASSISTANT. They can be generated by the end users
 // of an application, or set by a developer as an instruction.
 const USER Role = "user"
@@ -17,13 +22,18 @@ const USER Role = "user"
 const ASSISTANT Role = "assistant"
 
 // Roles is a list of all valid Roles.
-var Roles = []Role{SYSTEM, USER, ASSISTANT}
+var Roles = []Role{SYSTEM, DEVELOPER, USER, ASSISTANT}
 
 // String returns the string representation of a Role.
 func (r Role) String() string {
 	return string(r)
 }
 
+// IsInstruction returns true if the Role is either SYSTEM or DEVELOPER.
+func (r Role) IsInstruction() bool {
+	return r == SYSTEM || r == DEVELOPER
+}
+
 // IsValid returns true if the Role is valid.
 func (r Role) IsValid() bool {
 	for _, role := range Roles {
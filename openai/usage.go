@@ -0,0 +1,183 @@
+package openai
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UsageRequest contains the query parameters for the organization usage and costs
+// endpoints. These endpoints require an admin API key (see Client.AdminKey).
+type UsageRequest struct {
+	// StartTime is the start of the time range (inclusive, required field).
+	StartTime time.Time
+
+	// EndTime is the end of the time range (exclusive). If zero, the current time is used.
+	EndTime time.Time
+
+	// BucketWidth is the width of each time bucket: "1m", "1h", or "1d" (the default).
+	// The costs endpoint only supports "1d".
+	BucketWidth string
+
+	// GroupBy is a list of fields used to group the results, e.g. "model" or "project_id".
+	// The costs endpoint supports "project_id" and "line_item".
+	GroupBy []string
+
+	// ProjectIDs restricts the results to the specified projects.
+	ProjectIDs []string
+
+	// Models restricts the results to the specified models (usage endpoints only).
+	Models []string
+
+	// Batch restricts completions usage to batch (true) or non-batch (false) requests.
+	Batch *bool
+
+	// Limit is the number of buckets to return per page.
+	Limit int
+
+	// Page is a cursor for pagination, from the NextPage of a previous response.
+	Page string
+}
+
+// Query returns the URL-encoded query string for the UsageRequest.
+func (r UsageRequest) Query() string {
+	q := url.Values{}
+	q.Set("start_time", strconv.FormatInt(r.StartTime.Unix(), 10))
+	if !r.EndTime.IsZero() {
+		q.Set("end_time", strconv.FormatInt(r.EndTime.Unix(), 10))
+	}
+	if r.BucketWidth != "" {
+		q.Set("bucket_width", r.BucketWidth)
+	}
+	for _, g := range r.GroupBy {
+		q.Add("group_by", g)
+	}
+	for _, p := range r.ProjectIDs {
+		q.Add("project_ids", p)
+	}
+	for _, m := range r.Models {
+		q.Add("models", m)
+	}
+	if r.Batch != nil {
+		q.Set("batch", strconv.FormatBool(*r.Batch))
+	}
+	if r.Limit > 0 {
+		q.Set("limit", strconv.Itoa(r.Limit))
+	}
+	if r.Page != "" {
+		q.Set("page", r.Page)
+	}
+	return q.Encode()
+}
+
+// UsagePage is a page of time buckets returned by the usage and costs endpoints.
+type UsagePage struct {
+	Object   string        `json:"object"`    // "page" is expected
+	Data     []UsageBucket `json:"data"`      // list of time buckets
+	HasMore  bool          `json:"has_more"`  // true if there are more buckets to retrieve
+	NextPage string        `json:"next_page"` // use with the "page" query parameter
+}
+
+// UsageBucket is a time bucket of usage or cost results.
+type UsageBucket struct {
+	Object    string        `json:"object"`     // "bucket" is expected
+	StartTime int64         `json:"start_time"` // epoch seconds
+	EndTime   int64         `json:"end_time"`   // epoch seconds
+	Results   []UsageResult `json:"results"`    // one result per group
+}
+
+// Start returns the start time of the bucket.
+func (b UsageBucket) Start() time.Time {
+	return time.Unix(b.StartTime, 0).UTC()
+}
+
+// UsageResult is an aggregated usage or cost result for a time bucket. The
+// grouping fields (e.g. Model, ProjectID) are populated only when grouped.
+type UsageResult struct {
+	// Object is the result type, e.g. "organization.usage.completions.result" or
+	// "organization.costs.result".
+	Object string `json:"object"`
+
+	// InputTokens is the number of input tokens used, including cached tokens.
+	InputTokens int64 `json:"input_tokens,omitempty"`
+
+	// InputCachedTokens is the number of cached input tokens used.
+	InputCachedTokens int64 `json:"input_cached_tokens,omitempty"`
+
+	// OutputTokens is the number of output tokens used.
+	OutputTokens int64 `json:"output_tokens,omitempty"`
+
+	// NumModelRequests is the number of requests made to the model.
+	NumModelRequests int64 `json:"num_model_requests,omitempty"`
+
+	// Amount is the cost of the result (costs endpoint only).
+	Amount *CostAmount `json:"amount,omitempty"`
+
+	// LineItem is the cost line item, e.g. "gpt-4o-mini, input" (costs endpoint only).
+	LineItem string `json:"line_item,omitempty"`
+
+	// ProjectID is the project ID, when grouped by "project_id".
+	ProjectID string `json:"project_id,omitempty"`
+
+	// Model is the model ID, when grouped by "model".
+	Model string `json:"model,omitempty"`
+
+	// APIKeyID is the API key ID, when grouped by "api_key_id".
+	APIKeyID string `json:"api_key_id,omitempty"`
+
+	// UserID is the user ID, when grouped by "user_id".
+	UserID string `json:"user_id,omitempty"`
+
+	// Batch indicates batch requests, when grouped by "batch".
+	Batch *bool `json:"batch,omitempty"`
+}
+
+// Group returns a label identifying the result's group for the specified
+// group_by fields, e.g. "proj_abc123 gpt-4o-mini".
+func (r UsageResult) Group(groupBy ...string) string {
+	var parts []string
+	for _, g := range groupBy {
+		switch g {
+		case "project_id":
+			parts = append(parts, r.ProjectID)
+		case "model":
+			parts = append(parts, r.Model)
+		case "line_item":
+			parts = append(parts, r.LineItem)
+		case "api_key_id":
+			parts = append(parts, r.APIKeyID)
+		case "user_id":
+			parts = append(parts, r.UserID)
+		case "batch":
+			parts = append(parts, fmt.Sprint(r.Batch != nil && *r.Batch))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CostAmount is a monetary amount.
+type CostAmount struct {
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"` // "usd" is expected
+}
+
+// Project provides information about a project in the organization.
+type Project struct {
+	ID         string `json:"id"`
+	Object     string `json:"object"` // "organization.project" is expected
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"created_at"`
+	ArchivedAt int64  `json:"archived_at,omitempty"`
+	Status     string `json:"status"` // "active" or "archived"
+}
+
+// ProjectList is a list of projects in the organization.
+type ProjectList struct {
+	Object  string    `json:"object"`   // "list" is expected
+	Data    []Project `json:"data"`     // list of projects
+	FirstID string    `json:"first_id"` // first project ID in the collection
+	LastID  string    `json:"last_id"`  // use with the "after" query parameter
+	HasMore bool      `json:"has_more"` // true if there are more projects to retrieve
+}
@@ -0,0 +1,37 @@
+package openai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestUsageRequestQuery(t *testing.T) {
+	expect := assert.New(t)
+	batch := true
+	r := UsageRequest{
+		StartTime:   time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		BucketWidth: "1d",
+		GroupBy:     []string{"project_id", "model"},
+		Batch:       &batch,
+		Page:        "page_abc",
+	}
+	q, err := url.ParseQuery(r.Query())
+	if expect.NoError(err) {
+		expect.Equal("1756684800", q.Get("start_time"))
+		expect.Equal("1759276800", q.Get("end_time"))
+		expect.Equal([]string{"project_id", "model"}, q["group_by"])
+		expect.Equal("true", q.Get("batch"))
+		expect.Equal("page_abc", q.Get("page"))
+		expect.False(q.Has("limit"))
+	}
+}
+
+func TestUsageResultGroup(t *testing.T) {
+	expect := assert.New(t)
+	r := UsageResult{ProjectID: "proj_abc", Model: "gpt-4o-mini"}
+	expect.Equal("proj_abc gpt-4o-mini", r.Group("project_id", "model"))
+	expect.Equal("", r.Group())
+}
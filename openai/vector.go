@@ -0,0 +1,327 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VectorStore provides information about an OpenAI vector store, a collection
+// of processed files that can be used with the file search tool.
+type VectorStore struct {
+	// ID is the vector store ID, e.g. "vs_abc123".
+	ID string `json:"id"`
+
+	// Object is the object type, e.g. "vector_store".
+	Object string `json:"object"`
+
+	// Name is the name of the vector store.
+	Name string `json:"name"`
+
+	// Status is the status of the vector store: "expired", "in_progress", or "completed".
+	// A status of "completed" indicates that the vector store is ready for use.
+	Status string `json:"status"`
+
+	// UsageBytes is the total number of bytes used by the files in the vector store.
+	UsageBytes int64 `json:"usage_bytes"`
+
+	// FileCounts provides the number of files in the vector store, by status.
+	FileCounts FileCounts `json:"file_counts"`
+
+	// CreatedAt is a creation timestamp in epoch seconds, e.g. 1669599635.
+	CreatedAt int64 `json:"created_at"`
+
+	// LastActiveAt is a timestamp in epoch seconds when the vector store was last active.
+	LastActiveAt int64 `json:"last_active_at,omitempty"`
+
+	// ExpiresAfter is the expiration policy for the vector store.
+	ExpiresAfter *ExpiresAfter `json:"expires_after,omitempty"`
+
+	// ExpiresAt is a timestamp in epoch seconds when the vector store will expire.
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+
+	// Metadata is a map of up to 16 key-value pairs to include with the vector store.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// String provides a simple text display of the VectorStore intended for console output.
+func (v VectorStore) String() string {
+	createdAt := time.Unix(v.CreatedAt, 0).Format(time.DateTime)
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t%d files\t%d bytes", v.ID, v.Name, createdAt, v.Status,
+		v.FileCounts.Total, v.UsageBytes)
+}
+
+// VectorStoreList is a list of vector stores that belong to the user's organization.
+type VectorStoreList struct {
+	Object  string        `json:"object"`   // "list" is expected
+	Data    []VectorStore `json:"data"`     // list of vector stores
+	FirstID string        `json:"first_id"` // first vector store ID in the collection
+	LastID  string        `json:"last_id"`  // use with the "after" query parameter
+	HasMore bool          `json:"has_more"` // true if there are more vector stores to retrieve
+}
+
+// VectorStoreRequest contains the fields used to create a vector store.
+type VectorStoreRequest struct {
+	// Name is the name of the vector store.
+	Name string `json:"name,omitempty"`
+
+	// FileIDs is a list of uploaded file IDs to include in the vector store.
+	FileIDs []string `json:"file_ids,omitempty"`
+
+	// ExpiresAfter is the expiration policy for the vector store.
+	ExpiresAfter *ExpiresAfter `json:"expires_after,omitempty"`
+
+	// ChunkingStrategy identifies how the files are divided into chunks.
+	// If not provided, the "auto" strategy is used.
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+
+	// Metadata is a map of up to 16 key-value pairs to include with the vector store.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ExpiresAfter is the expiration policy for a vector store.
+type ExpiresAfter struct {
+	// Anchor is the timestamp after which the expiration policy applies.
+	// The supported anchor is "last_active_at".
+	Anchor string `json:"anchor"`
+
+	// Days is the number of days after the anchor time that the vector store will expire.
+	Days int `json:"days"`
+}
+
+// ChunkingStrategy identifies how files are divided into chunks for a vector store.
+type ChunkingStrategy struct {
+	// Type is the chunking strategy type: "auto" or "static".
+	Type string `json:"type"`
+
+	// Static provides the chunking parameters for the "static" strategy.
+	Static *StaticChunking `json:"static,omitempty"`
+}
+
+// StaticChunking provides the parameters for the static chunking strategy.
+type StaticChunking struct {
+	// MaxChunkSizeTokens is the maximum number of tokens in each chunk,
+	// between 100 and 4096. The default is 800.
+	MaxChunkSizeTokens int `json:"max_chunk_size_tokens"`
+
+	// ChunkOverlapTokens is the number of tokens that overlap between chunks.
+	// It must not exceed half of MaxChunkSizeTokens. The default is 400.
+	ChunkOverlapTokens int `json:"chunk_overlap_tokens"`
+}
+
+// FileCounts provides the number of files in a vector store or file batch, by status.
+type FileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+// String returns a string representation of the FileCounts.
+func (f FileCounts) String() string {
+	return fmt.Sprintf("%d total, %d completed, %d in progress, %d failed, %d cancelled",
+		f.Total, f.Completed, f.InProgress, f.Failed, f.Cancelled)
+}
+
+// VectorStoreFile provides information about a file attached to a vector store.
+type VectorStoreFile struct {
+	// ID is the file ID, e.g. "file-abc123".
+	ID string `json:"id"`
+
+	// Object is the object type, e.g. "vector_store.file".
+	Object string `json:"object"`
+
+	// VectorStoreID is the ID of the vector store that the file is attached to.
+	VectorStoreID string `json:"vector_store_id"`
+
+	// Status is the status of the file: "in_progress", "completed", "cancelled", or "failed".
+	// A status of "completed" indicates that the file is ready for use.
+	Status string `json:"status"`
+
+	// UsageBytes is the number of bytes used by the file in the vector store.
+	UsageBytes int64 `json:"usage_bytes"`
+
+	// CreatedAt is a creation timestamp in epoch seconds, e.g. 1669599635.
+	CreatedAt int64 `json:"created_at"`
+
+	// LastError is the last error associated with the file, if any.
+	LastError *VectorStoreError `json:"last_error,omitempty"`
+
+	// ChunkingStrategy identifies how the file was divided into chunks.
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+
+	// Attributes is a map of up to 16 key-value pairs that can be used to filter search results.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// VectorStoreFileList is a list of files attached to a vector store.
+type VectorStoreFileList struct {
+	Object  string            `json:"object"`   // "list" is expected
+	Data    []VectorStoreFile `json:"data"`     // list of vector store files
+	FirstID string            `json:"first_id"` // first file ID in the collection
+	LastID  string            `json:"last_id"`  // use with the "after" query parameter
+	HasMore bool              `json:"has_more"` // true if there are more files to retrieve
+}
+
+// VectorStoreFileRequest contains the fields used to attach one or more files to a vector store.
+// Use FileID for a single file, or FileIDs for a file batch.
+type VectorStoreFileRequest struct {
+	// FileID is the ID of an uploaded file to attach to the vector store.
+	FileID string `json:"file_id,omitempty"`
+
+	// FileIDs is a list of uploaded file IDs to attach to the vector store as a batch.
+	FileIDs []string `json:"file_ids,omitempty"`
+
+	// Attributes is a map of up to 16 key-value pairs that can be used to filter search results.
+	Attributes map[string]any `json:"attributes,omitempty"`
+
+	// ChunkingStrategy identifies how the files are divided into chunks.
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+}
+
+// VectorStoreError provides information about an error processing a vector store file.
+type VectorStoreError struct {
+	// Code is the error code, e.g. "server_error", "unsupported_file", or "invalid_file".
+	Code string `json:"code"`
+
+	// Message is a human-readable description of the error.
+	Message string `json:"message"`
+}
+
+// Error returns the VectorStoreError message.
+func (e VectorStoreError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// VectorStoreFileBatch provides information about a batch of files attached to a vector store.
+type VectorStoreFileBatch struct {
+	// ID is the file batch ID, e.g. "vsfb_abc123".
+	ID string `json:"id"`
+
+	// Object is the object type, e.g. "vector_store.files_batch".
+	Object string `json:"object"`
+
+	// VectorStoreID is the ID of the vector store that the files are attached to.
+	VectorStoreID string `json:"vector_store_id"`
+
+	// Status is the status of the file batch: "in_progress", "completed", "cancelled", or "failed".
+	Status string `json:"status"`
+
+	// FileCounts provides the number of files in the batch, by status.
+	FileCounts FileCounts `json:"file_counts"`
+
+	// CreatedAt is a creation timestamp in epoch seconds, e.g. 1669599635.
+	CreatedAt int64 `json:"created_at"`
+}
+
+// Progress provides information about the progress of a file batch.
+func (b *VectorStoreFileBatch) Progress() string {
+	return fmt.Sprintf("%s %s, %s", b.ID, b.Status, b.FileCounts)
+}
+
+// IsDone returns true if the file batch has completed, failed, or been cancelled.
+func (b *VectorStoreFileBatch) IsDone() bool {
+	switch b.Status {
+	case "completed", "failed", "cancelled":
+		return true
+	}
+	return false
+}
+
+// VectorStoreSearchRequest contains the fields used to search a vector store.
+type VectorStoreSearchRequest struct {
+	// Query is the query string for the search (required field).
+	Query string `json:"query"`
+
+	// MaxNumResults is the maximum number of results to return, between 1 and 50. The default is 10.
+	MaxNumResults int `json:"max_num_results,omitempty"`
+
+	// RewriteQuery indicates whether to rewrite the natural language query for vector search.
+	RewriteQuery bool `json:"rewrite_query,omitempty"`
+
+	// Filters is an optional attribute filter, e.g. {"type": "eq", "key": "kind", "value": "codebook"}.
+	Filters map[string]any `json:"filters,omitempty"`
+
+	// RankingOptions provides options for ranking the search results.
+	RankingOptions *RankingOptions `json:"ranking_options,omitempty"`
+}
+
+// RankingOptions provides options for ranking vector store search results.
+type RankingOptions struct {
+	// Ranker is the ranker to use, e.g. "auto".
+	Ranker string `json:"ranker,omitempty"`
+
+	// ScoreThreshold is the minimum score (between 0 and 1) of the results to return.
+	ScoreThreshold float64 `json:"score_threshold,omitempty"`
+}
+
+// VectorStoreSearchResults is a page of vector store search results.
+type VectorStoreSearchResults struct {
+	Object      string                    `json:"object"`       // "vector_store.search_results.page" is expected
+	SearchQuery SearchQuery               `json:"search_query"` // the (possibly rewritten) search query
+	Data        []VectorStoreSearchResult `json:"data"`         // list of search results
+	HasMore     bool                      `json:"has_more"`     // true if there are more results
+	NextPage    string                    `json:"next_page,omitempty"`
+}
+
+// SearchQuery is a list of search query strings. It may be provided as a single string.
+type SearchQuery []string
+
+// UnmarshalJSON decodes a SearchQuery from either a JSON string or an array of strings.
+func (q *SearchQuery) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*q = SearchQuery{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*q = list
+	return nil
+}
+
+// VectorStoreSearchResult is a single vector store search result.
+type VectorStoreSearchResult struct {
+	// FileID is the ID of the file containing the result.
+	FileID string `json:"file_id"`
+
+	// FileName is the name of the file containing the result.
+	FileName string `json:"filename"`
+
+	// Score is the similarity score of the result, between 0 and 1.
+	Score float64 `json:"score"`
+
+	// Attributes is the map of file attributes.
+	Attributes map[string]any `json:"attributes,omitempty"`
+
+	// Content is a list of the content chunks that matched the query.
+	Content []SearchContent `json:"content"`
+}
+
+// Text returns the text of the matching content chunks.
+func (r VectorStoreSearchResult) Text() string {
+	var parts []string
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			parts = append(parts, strings.TrimSpace(c.Text))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// SearchContent is a content chunk of a vector store search result.
+type SearchContent struct {
+	Type string `json:"type"` // "text" is expected
+	Text string `json:"text"`
+}
+
+// DeleteStatus is the response to a request to delete an object.
+type DeleteStatus struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"` // e.g. "vector_store.deleted"
+	Deleted bool   `json:"deleted"`
+}
@@ -0,0 +1,146 @@
+package openai
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook event types of interest. See https://platform.openai.com/docs/guides/webhooks
+const (
+	EventBatchCompleted      = "batch.completed"
+	EventBatchFailed         = "batch.failed"
+	EventBatchCancelled      = "batch.cancelled"
+	EventBatchExpired        = "batch.expired"
+	EventFineTuningSucceeded = "fine_tuning.job.succeeded"
+	EventFineTuningFailed    = "fine_tuning.job.failed"
+	EventFineTuningCancelled = "fine_tuning.job.cancelled"
+)
+
+// DefaultWebhookTolerance is the default maximum age of a webhook timestamp.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// Standard Webhooks headers and signature format.
+const (
+	webhookSecretPrefix       = "whsec_"
+	webhookIDHeader           = "webhook-id"
+	webhookTimestampHeader    = "webhook-timestamp"
+	webhookSignatureHeader    = "webhook-signature"
+	webhookSignatureVersion   = "v1,"
+	webhookSignatureSeparator = " "
+)
+
+// ErrInvalidWebhookSignature indicates that a webhook could not be verified.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookEvent is an event notification delivered by an OpenAI webhook.
+type WebhookEvent struct {
+	// ID is the event ID, e.g. "evt_abc123".
+	ID string `json:"id"`
+
+	// Object is the object type, e.g. "event".
+	Object string `json:"object"`
+
+	// Type is the event type, e.g. "batch.completed".
+	Type string `json:"type"`
+
+	// CreatedAt is a creation timestamp in epoch seconds, e.g. 1669599635.
+	CreatedAt int64 `json:"created_at"`
+
+	// Data identifies the object of the event, e.g. a batch or fine-tuning job.
+	Data WebhookEventData `json:"data"`
+}
+
+// WebhookEventData identifies the object of a webhook event.
+type WebhookEventData struct {
+	// ID is the object ID, e.g. "batch_abc123" or "ftjob_abc123".
+	ID string `json:"id"`
+}
+
+// IsBatch returns true if the event is a batch event.
+func (e WebhookEvent) IsBatch() bool {
+	return strings.HasPrefix(e.Type, "batch.")
+}
+
+// IsFineTuning returns true if the event is a fine-tuning job event.
+func (e WebhookEvent) IsFineTuning() bool {
+	return strings.HasPrefix(e.Type, "fine_tuning.job.")
+}
+
+// String provides a simple text display of the WebhookEvent intended for console output.
+func (e WebhookEvent) String() string {
+	createdAt := time.Unix(e.CreatedAt, 0).Format(time.DateTime)
+	return fmt.Sprintf("%s\t%s\t%s\t%s", e.ID, createdAt, e.Type, e.Data.ID)
+}
+
+// UnwrapWebhook verifies the signature of a webhook request and decodes its event.
+func UnwrapWebhook(secret string, header http.Header, body []byte, tolerance time.Duration) (WebhookEvent, error) {
+	var event WebhookEvent
+	if err := VerifyWebhook(secret, header, body, tolerance, time.Now()); err != nil {
+		return event, err
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return event, fmt.Errorf("unwrap webhook: unmarshal event: %w", err)
+	}
+	return event, nil
+}
+
+// VerifyWebhook verifies the signature of a webhook request using the Standard
+// Webhooks scheme: an HMAC-SHA256 of the webhook ID, timestamp, and body, keyed
+// with the webhook secret. The timestamp must be within the tolerance of now,
+// to prevent replay attacks. A non-positive tolerance disables the check.
+func VerifyWebhook(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	id := header.Get(webhookIDHeader)
+	timestamp := header.Get(webhookTimestampHeader)
+	signatures := header.Get(webhookSignatureHeader)
+	if id == "" || timestamp == "" || signatures == "" {
+		return fmt.Errorf("%w: missing webhook headers", ErrInvalidWebhookSignature)
+	}
+
+	// Verify the timestamp:
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %s", ErrInvalidWebhookSignature, timestamp)
+	}
+	if tolerance > 0 {
+		sent := time.Unix(seconds, 0)
+		if now.Sub(sent) > tolerance {
+			return fmt.Errorf("%w: timestamp %s is too old", ErrInvalidWebhookSignature, timestamp)
+		}
+		if sent.Sub(now) > tolerance {
+			return fmt.Errorf("%w: timestamp %s is too new", ErrInvalidWebhookSignature, timestamp)
+		}
+	}
+
+	// Verify the signature (one of possibly several, during secret rotation):
+	expected, err := SignWebhook(secret, id, seconds, body)
+	if err != nil {
+		return err
+	}
+	for _, s := range strings.Split(signatures, webhookSignatureSeparator) {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidWebhookSignature
+}
+
+// SignWebhook computes the versioned signature (e.g. "v1,K5oZfz...") of a webhook
+// with the specified ID, timestamp, and body, using the webhook secret.
+func SignWebhook(secret, id string, timestamp int64, body []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, webhookSecretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook secret: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return webhookSignatureVersion + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,46 @@
+package openai
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+
+func signedHeader(t *testing.T, id string, sent time.Time, body []byte) http.Header {
+	sig, err := SignWebhook(testWebhookSecret, id, sent.Unix(), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := http.Header{}
+	h.Set("webhook-id", id)
+	h.Set("webhook-timestamp", strconv.FormatInt(sent.Unix(), 10))
+	h.Set("webhook-signature", "v1,b2xkc2lnbmF0dXJl "+sig)
+	return h
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	expect := assert.New(t)
+	now := time.Now()
+	body := []byte(`{"id":"evt_1","object":"event","type":"batch.completed","created_at":1,"data":{"id":"batch_1"}}`)
+	h := signedHeader(t, "wh_1", now.Add(-time.Minute), body)
+	expect.NoError(VerifyWebhook(testWebhookSecret, h, body, DefaultWebhookTolerance, now))
+	expect.ErrorIs(VerifyWebhook(testWebhookSecret, h, []byte(`{"tampered":true}`), DefaultWebhookTolerance, now),
+		ErrInvalidWebhookSignature, "Tampered body")
+	expect.ErrorIs(VerifyWebhook(testWebhookSecret, h, body, DefaultWebhookTolerance, now.Add(time.Hour)),
+		ErrInvalidWebhookSignature, "Expired timestamp")
+	expect.NoError(VerifyWebhook(testWebhookSecret, h, body, 0, now.Add(time.Hour)), "Tolerance disabled")
+	expect.ErrorIs(VerifyWebhook(testWebhookSecret, http.Header{}, body, DefaultWebhookTolerance, now),
+		ErrInvalidWebhookSignature, "Missing headers")
+
+	event, err := UnwrapWebhook(testWebhookSecret, signedHeader(t, "wh_2", time.Now(), body), body, DefaultWebhookTolerance)
+	if expect.NoError(err) {
+		expect.Equal(EventBatchCompleted, event.Type)
+		expect.Equal("batch_1", event.Data.ID)
+		expect.True(event.IsBatch())
+		expect.False(event.IsFineTuning())
+	}
+}
@@ -1,31 +1,50 @@
 package psy
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"gpt/openai"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 // ChatParameters represents the parameters for chat prompts and completions.
 type ChatParameters struct {
-	InputFile     string    `json:"inputFile,omitempty"`     // input file name
-	OutputFile    string    `json:"outputFile,omitempty"`    // output file name
-	SystemFile    string    `json:"systemFile,omitempty"`    // system message file
-	PromptFile    string    `json:"promptFile,omitempty"`    // prompt template file
-	QuestionFile  string    `json:"questionFile,omitempty"`  // question template file
-	QuestionField string    `json:"questionField,omitempty"` // question field name
-	QuestionID    string    `json:"questionID,omitempty"`    // question ID
-	AnswerFile    string    `json:"answerFile,omitempty"`    // answer template file
-	AnswerField   string    `json:"answerField,omitempty"`   // answer field name
-	AnswerID      string    `json:"answerID,omitempty"`      // answer ID
-	ScoreField    string    `json:"scoreField,omitempty"`    // score field name
-	ScoreSelect   Selection `json:"scoreSelect,omitempty"`   // score selection
-	Model         string    `json:"model,omitempty"`         // model ID
-	Temperature   float32   `json:"temperature,omitempty"`   // temperature
-	MaxTokens     int       `json:"maxTokens,omitempty"`     // maximum tokens
+	InputFile        string                 `json:"inputFile,omitempty"`        // input file name
+	OutputFile       string                 `json:"outputFile,omitempty"`       // output file name
+	SystemFile       string                 `json:"systemFile,omitempty"`       // system message file
+	PromptFile       string                 `json:"promptFile,omitempty"`       // prompt template file
+	ConversationFile string                 `json:"conversationFile,omitempty"` // conversation template file
+	QuestionFile     string                 `json:"questionFile,omitempty"`     // question template file
+	QuestionField    string                 `json:"questionField,omitempty"`    // question field name
+	QuestionID       string                 `json:"questionID,omitempty"`       // question ID
+	AnswerFile       string                 `json:"answerFile,omitempty"`       // answer template file
+	AnswerField      string                 `json:"answerField,omitempty"`      // answer field name
+	AnswerID         string                 `json:"answerID,omitempty"`         // answer ID
+	ScoreField       string                 `json:"scoreField,omitempty"`       // score field name
+	ScoreSelect      Selection              `json:"scoreSelect,omitempty"`      // score selection
+	Model            string                 `json:"model,omitempty"`            // model ID
+	Temperature      float32                `json:"temperature,omitempty"`      // temperature
+	MaxTokens        int                    `json:"maxTokens,omitempty"`        // maximum tokens
+	ReasoningEffort  openai.ReasoningEffort `json:"reasoningEffort,omitempty"`  // reasoning effort
+	Verbosity        openai.Verbosity       `json:"verbosity,omitempty"`        // response verbosity
+	Store            bool                   `json:"store,omitempty"`            // store completions?
+}
+
+// Abs returns a copy of the ChatParameters with absolute local file paths (see AbsPath).
+func (p ChatParameters) Abs() ChatParameters {
+	p.InputFile = AbsPath(p.InputFile)
+	p.OutputFile = AbsPath(p.OutputFile)
+	p.SystemFile = AbsPath(p.SystemFile)
+	p.PromptFile = AbsPath(p.PromptFile)
+	p.ConversationFile = AbsPath(p.ConversationFile)
+	p.QuestionFile = AbsPath(p.QuestionFile)
+	p.AnswerFile = AbsPath(p.AnswerFile)
+	return p
 }
 
 // Metadata returns a map of key-value pairs for the ChatParameters.
@@ -43,6 +62,9 @@ func (p ChatParameters) Metadata() map[string]string {
 	if len(p.PromptFile) > 0 {
 		m["prompt_file"] = p.PromptFile
 	}
+	if len(p.ConversationFile) > 0 {
+		m["conversation_file"] = p.ConversationFile
+	}
 	if len(p.QuestionFile) > 0 {
 		m["question_file"] = p.QuestionFile
 	}
@@ -70,15 +92,64 @@ func (p ChatParameters) Metadata() map[string]string {
 	if len(p.Model) > 0 {
 		m["model"] = p.Model
 	}
-	if p.Temperature > 0 {
+	if p.Temperature > 0 && !openai.Capabilities(p.Model).Reasoning {
 		m["temperature"] = fmt.Sprintf("%f", p.Temperature)
 	}
 	if p.MaxTokens > 0 {
 		m["max_tokens"] = strconv.Itoa(p.MaxTokens)
 	}
+	if len(p.ReasoningEffort) > 0 {
+		m["reasoning_effort"] = p.ReasoningEffort.String()
+	}
+	if len(p.Verbosity) > 0 {
+		m["verbosity"] = p.Verbosity.String()
+	}
 	return m
 }
 
+// MaxMetadataPairs is the maximum number of metadata key-value pairs accepted by OpenAI.
+const MaxMetadataPairs = 16
+
+// MaxMetadataValue is the maximum length of a metadata value accepted by OpenAI.
+const MaxMetadataValue = 512
+
+// metadataPriority lists the metadata keys in order of importance. Keys needed to
+// process results are kept first; unlisted keys are kept last, in sorted order.
+var metadataPriority = []string{
+	"chat_id", "run_id", "run_part", "run_parts", "retry_of", "output_file", "score_field", "score_select", "model", "input_file",
+	"reasoning_effort", "verbosity", "max_tokens", "temperature", "prompt_file",
+	"conversation_file", "system_file", "answer_file", "answer_field", "question_file", "question_field",
+	"question_id", "answer_id",
+}
+
+// LimitMetadata returns a copy of the metadata that OpenAI will accept: at most
+// MaxMetadataPairs pairs, retained in order of importance. Values longer than
+// MaxMetadataValue characters (e.g. deeply nested file paths) are dropped rather
+// than truncated, because a truncated file path would silently fail to match.
+// The full values are available from the local job registry.
+func LimitMetadata(m map[string]string) map[string]string {
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		if len(v) <= MaxMetadataValue {
+			keys = append(keys, k)
+		}
+	}
+	rank := func(k string) int {
+		if i := slices.Index(metadataPriority, k); i >= 0 {
+			return i
+		}
+		return len(metadataPriority)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(rank(a), rank(b)), strings.Compare(a, b))
+	})
+	limited := make(map[string]string, min(len(keys), MaxMetadataPairs))
+	for _, k := range keys[:min(len(keys), MaxMetadataPairs)] {
+		limited[k] = m[k]
+	}
+	return limited
+}
+
 // Chat represents a complete request/response chat exchange.
 type Chat struct {
 	ID       string              `json:"id,omitempty"` // batch-unique ID
@@ -109,8 +180,14 @@ func (c *Chat) String() string {
 	return s
 }
 
-// NewChat creates a new Chat object with a ChatRequest.
-func NewChat(id, system, prompt, model string, temperature float32, maxTokens int) Chat {
+// NewChat creates a new Chat object with a ChatRequest for a system message
+// (optional) and prompt. See NewChatMessages for details.
+func NewChat(id, system, prompt string, p ChatParameters) Chat {
+	return NewChatMessages(id, ChatMessages(system, prompt), p)
+}
+
+// ChatMessages returns the messages for a system message (optional) and prompt.
+func ChatMessages(system, prompt string) []openai.Message {
 	var messages []openai.Message
 	if len(system) > 0 {
 		messages = append(messages, openai.Message{
@@ -118,19 +195,38 @@ func NewChat(id, system, prompt, model string, temperature float32, maxTokens in
 			Content: system,
 		})
 	}
-	messages = append(messages, openai.Message{
+	return append(messages, openai.Message{
 		Role:    openai.USER,
 		Content: prompt,
 	})
+}
+
+// NewChatMessages creates a new Chat object with a ChatRequest for the messages
+// of a (possibly multi-turn) conversation. The model, temperature, maximum
+// tokens, reasoning effort, and verbosity are taken from the provided
+// ChatParameters, and the request is adapted to suit the model's capabilities.
+// If the completion is to be stored, the request is tagged with the parameter
+// metadata and the chat ID, for retrieval with Client.ListStoredCompletions.
+func NewChatMessages(id string, messages []openai.Message, p ChatParameters) Chat {
+	request := openai.ChatRequest{
+		Model:           p.Model,
+		Messages:        messages,
+		Temperature:     p.Temperature,
+		MaxTokens:       p.MaxTokens,
+		ReasoningEffort: p.ReasoningEffort,
+		Verbosity:       p.Verbosity,
+		User:            id,
+	}
+	if p.Store {
+		m := p.Metadata()
+		m["chat_id"] = id
+		request.Store = true
+		request.Metadata = LimitMetadata(m)
+	}
+	request.Adapt()
 	return Chat{
-		ID: id,
-		Request: openai.ChatRequest{
-			Model:       model,
-			Messages:    messages,
-			Temperature: temperature,
-			MaxTokens:   maxTokens,
-			User:        id,
-		},
+		ID:      id,
+		Request: request,
 	}
 }
 
@@ -0,0 +1,41 @@
+package psy
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestLimitMetadata(t *testing.T) {
+	expect := assert.New(t)
+	m := map[string]string{
+		"chat_id":     "c1",
+		"output_file": strings.Repeat("d/", 300) + "scores.csv",
+	}
+	for i := range 20 {
+		m[fmt.Sprintf("extra_%02d", i)] = "x"
+	}
+	limited := LimitMetadata(m)
+	expect.Len(limited, MaxMetadataPairs)
+	expect.Equal("c1", limited["chat_id"], "Priority key retained")
+	expect.NotContains(limited, "output_file", "Long value dropped, not truncated")
+	expect.Contains(limited, "extra_00", "Unlisted keys in sorted order")
+	expect.Contains(limited, "extra_14")
+	expect.NotContains(limited, "extra_15")
+	expect.Len(m, 22, "Original unchanged")
+}
+
+func TestNewChatStore(t *testing.T) {
+	expect := assert.New(t)
+	p := ChatParameters{OutputFile: "scores.csv", Model: "gpt-4o-mini", Store: true}
+	chat := NewChat("c1", "system", "prompt", p)
+	expect.True(chat.Request.Store)
+	expect.Equal("c1", chat.Request.Metadata["chat_id"])
+	expect.Equal("scores.csv", chat.Request.Metadata["output_file"])
+
+	p.Store = false
+	chat = NewChat("c2", "system", "prompt", p)
+	expect.False(chat.Request.Store)
+	expect.Nil(chat.Request.Metadata)
+}
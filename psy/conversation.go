@@ -0,0 +1,179 @@
+package psy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gpt/openai"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Conversation is a multi-turn chat template, read from a YAML or JSON file. It
+// lists the messages of a chat request, each of which may use the same fields
+// and functions as a prompt template. The final message must be from the user.
+// Few-shot examples may be inserted before the final message, as prior user and
+// assistant turns. For example:
+//
+//	messages:
+//	  - role: system
+//	    content: You rate the spirituality of written answers, from 1 to 5.
+//	  - role: user
+//	    content: "Question: {{question}}\nAnswer: {{answer}}"
+//	examples:
+//	  file: gold.csv
+//	  completion: "{{.score}}"
+//	  count: 3
+//	  sample: true
+type Conversation struct {
+	Messages  []ConversationMessage `json:"messages" yaml:"messages"`                     // message templates
+	Examples  *FewShot              `json:"examples,omitempty" yaml:"examples,omitempty"` // few-shot examples (optional)
+	Path      string                `json:"-" yaml:"-"`                                   // conversation file path
+	templates []*Template
+}
+
+// ConversationMessage is a message template in a conversation.
+type ConversationMessage struct {
+	Role    openai.Role `json:"role" yaml:"role"`       // system | developer | user | assistant
+	Content string      `json:"content" yaml:"content"` // message template
+}
+
+// FewShot specifies the few-shot examples of a conversation, drawn from a CSV
+// file of gold-scored answers with the same fields as the answer file. Each
+// example is rendered as a user message (with the final message template, by
+// default) and an assistant message with the gold completion.
+type FewShot struct {
+	File       string `json:"file" yaml:"file"`                         // CSV file, relative to the conversation file
+	Prompt     string `json:"prompt,omitempty" yaml:"prompt,omitempty"` // user message template (optional)
+	Completion string `json:"completion" yaml:"completion"`             // assistant message template, e.g. "{{.score}}"
+	Count      int    `json:"count,omitempty" yaml:"count,omitempty"`   // number of examples (default: all)
+	Sample     bool   `json:"sample,omitempty" yaml:"sample,omitempty"` // sample the examples for each request?
+	Seed       int64  `json:"seed,omitempty" yaml:"seed,omitempty"`     // seed for sampling (default: random)
+	prompt     *Template
+	completion *Template
+}
+
+// ReadConversation reads, validates, and parses a conversation template file.
+// The few-shot example file and included files are relative to its directory.
+func ReadConversation(path string) (*Conversation, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read conversation %s: %w", path, err)
+	}
+	c := &Conversation{Path: path}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.DisallowUnknownFields()
+		err = d.Decode(c)
+	} else {
+		d := yaml.NewDecoder(bytes.NewReader(b))
+		d.KnownFields(true)
+		err = d.Decode(c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse conversation %s: %w", path, err)
+	}
+	if err = c.Validate(); err != nil {
+		return nil, fmt.Errorf("conversation %s: %w", path, err)
+	}
+
+	// Parse the message templates:
+	dir := filepath.Dir(path)
+	for i, m := range c.Messages {
+		t, err := NewTemplate(fmt.Sprintf("message %d", i+1), m.Content, dir)
+		if err != nil {
+			return nil, fmt.Errorf("conversation %s: %w", path, err)
+		}
+		c.templates = append(c.templates, t)
+	}
+	if e := c.Examples; e != nil {
+		if !filepath.IsAbs(e.File) {
+			e.File = filepath.Join(dir, e.File)
+		}
+		e.prompt = c.templates[len(c.templates)-1]
+		if e.Prompt != "" {
+			if e.prompt, err = NewTemplate("examples prompt", e.Prompt, dir); err != nil {
+				return nil, fmt.Errorf("conversation %s: %w", path, err)
+			}
+		}
+		if e.completion, err = NewTemplate("examples completion", e.Completion, dir); err != nil {
+			return nil, fmt.Errorf("conversation %s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// Validate checks the conversation's messages and few-shot example settings.
+func (c *Conversation) Validate() error {
+	if len(c.Messages) == 0 {
+		return fmt.Errorf("no messages")
+	}
+	for i, m := range c.Messages {
+		if !m.Role.IsValid() {
+			return fmt.Errorf("message %d: invalid role %q: expecting system | developer | user | assistant", i+1, m.Role)
+		}
+		if strings.TrimSpace(m.Content) == "" {
+			return fmt.Errorf("message %d: missing content", i+1)
+		}
+	}
+	if c.Messages[len(c.Messages)-1].Role != openai.USER {
+		return fmt.Errorf("the final message must be from the user")
+	}
+	if e := c.Examples; e != nil {
+		switch {
+		case e.File == "":
+			return fmt.Errorf("examples: missing file")
+		case strings.TrimSpace(e.Completion) == "":
+			return fmt.Errorf("examples: missing completion template")
+		case e.Count < 0:
+			return fmt.Errorf("examples: invalid count %d", e.Count)
+		}
+	}
+	return nil
+}
+
+// Files returns the paths of the local files that the conversation depends on: its
+// few-shot examples file, and the files included by its templates (see Template.Files).
+func (c *Conversation) Files() []string {
+	var files []string
+	for _, t := range c.templates {
+		files = append(files, t.Files()...)
+	}
+	if e := c.Examples; e != nil {
+		files = slices.Concat(files, []string{e.File}, e.prompt.Files(), e.completion.Files())
+	}
+	return files
+}
+
+// Render renders the conversation's messages with the record's fields, with the
+// few-shot examples (if any) inserted before the final message.
+func (c *Conversation) Render(r Record, examples []Record) ([]openai.Message, error) {
+	messages := make([]openai.Message, 0, len(c.templates)+2*len(examples))
+	for i, t := range c.templates {
+		if i == len(c.templates)-1 {
+			for j, x := range examples {
+				prompt, err := c.Examples.prompt.Execute(x)
+				if err != nil {
+					return nil, fmt.Errorf("example %d: %w", j+1, err)
+				}
+				completion, err := c.Examples.completion.Execute(x)
+				if err != nil {
+					return nil, fmt.Errorf("example %d: %w", j+1, err)
+				}
+				messages = append(messages,
+					openai.Message{Role: openai.USER, Content: prompt},
+					openai.Message{Role: openai.ASSISTANT, Content: strings.TrimSpace(completion)})
+			}
+		}
+		content, err := t.Execute(r)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		messages = append(messages, openai.Message{Role: c.Messages[i].Role, Content: content})
+	}
+	return messages, nil
+}
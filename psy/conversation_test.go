@@ -0,0 +1,117 @@
+package psy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"gpt/openai"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConversation(t *testing.T) {
+	expect := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "conversation.yaml")
+	_ = os.WriteFile(path, []byte(`messages:
+  - role: system
+    content: Rate the answer about {{.Concept}}.
+  - role: user
+    content: "A: {{answer}}"
+examples:
+  file: gold.csv
+  completion: "{{.score}}"
+  count: 2
+`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "gold.csv"), []byte("Concept,answer,score\nLife,Yes,5\nWord,No,1\nLife,Maybe,3\n"), 0644)
+
+	pr, err := NewPrompter(ChatParameters{ConversationFile: path, AnswerField: "answer"})
+	if !expect.NoError(err) {
+		return
+	}
+	messages, ok, err := pr.Messages(Record{"Concept": "Life", "answer": " No "})
+	expect.NoError(err)
+	expect.True(ok)
+	expect.Equal([]openai.Message{
+		{Role: openai.SYSTEM, Content: "Rate the answer about Life."},
+		{Role: openai.USER, Content: "A: Yes"},
+		{Role: openai.ASSISTANT, Content: "5"},
+		{Role: openai.USER, Content: "A: Maybe"},
+		{Role: openai.ASSISTANT, Content: "3"},
+		{Role: openai.USER, Content: "A: No"},
+	}, messages, "Fixed examples, excluding the same answer")
+	_, ok, err = pr.Messages(Record{"Concept": "Life", "answer": ""})
+	expect.NoError(err)
+	expect.False(ok, "Blank answer")
+	_, _, err = pr.Messages(Record{"answer": "No"})
+	expect.ErrorContains(err, "Concept", "Missing field")
+	_, _, err = pr.Prompt(Record{"answer": "No"})
+	expect.Error(err, "Single prompt")
+
+	// Sampled examples are reproducible with the same seed:
+	pr.Conversation.Examples.Sample, pr.Conversation.Examples.Count = true, 1
+	sampled := func(seed int64) []string {
+		pr.rng = rand.New(rand.NewSource(seed))
+		var answers []string
+		for range 10 {
+			messages, _, _ = pr.Messages(Record{"Concept": "Word", "answer": "Other"})
+			answers = append(answers, messages[1].Content)
+		}
+		return answers
+	}
+	expect.Equal(sampled(3), sampled(3), "Reproducible")
+	expect.Len(messages, 4, "Sampled count")
+
+	// Invalid conversations:
+	_ = os.WriteFile(path, []byte("messages:\n  - role: user\n    content: Hi\n  - role: assistant\n    content: Hello\n"), 0644)
+	_, err = ReadConversation(path)
+	expect.ErrorContains(err, "final message")
+	_ = os.WriteFile(path, []byte("messages:\n  - role: robot\n    content: Hi\n"), 0644)
+	_, err = ReadConversation(path)
+	expect.ErrorContains(err, "invalid role")
+	jsonPath := filepath.Join(dir, "conversation.json")
+	_ = os.WriteFile(jsonPath, []byte(`{"messages": [{"role": "user", "content": "{{upper answer}}"}], "extra": 1}`), 0644)
+	_, err = ReadConversation(jsonPath)
+	expect.ErrorContains(err, "extra", "Unknown field")
+	_ = os.WriteFile(jsonPath, []byte(`{"messages": [{"role": "user", "content": "{{upper .answer}}"}]}`), 0644)
+	c, err := ReadConversation(jsonPath)
+	if expect.NoError(err) {
+		messages, err = c.Render(Record{"answer": "yes"}, nil)
+		expect.NoError(err)
+		expect.Equal([]openai.Message{{Role: openai.USER, Content: "YES"}}, messages, "JSON")
+	}
+
+	// A prompt file is a single prompt template, whatever its extension:
+	pr, err = NewPrompter(ChatParameters{PromptFile: jsonPath, AnswerField: "answer"})
+	if expect.NoError(err) {
+		prompt, _, err := pr.Prompt(Record{"answer": "yes"})
+		expect.NoError(err)
+		expect.Equal(`{"messages": [{"role": "user", "content": "YES"}]}`, prompt)
+	}
+	_, err = NewPrompter(ChatParameters{ConversationFile: jsonPath, PromptFile: path, AnswerField: "answer"})
+	expect.Error(err, "Prompt file with a conversation")
+}
+
+func TestConversationFiles(t *testing.T) {
+	expect := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "conversation.yaml")
+	_ = os.WriteFile(path, []byte(`messages:
+  - role: system
+    content: '{{include "rubric.txt" .}}'
+  - role: user
+    content: "A: {{answer}}"
+examples:
+  file: gold.csv
+  completion: "{{.score}}"
+`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "rubric.txt"), []byte(`Rate it. {{include "scale.txt"}}`), 0644)
+	c, err := ReadConversation(path)
+	if expect.NoError(err) {
+		expect.Equal([]string{filepath.Join(dir, "rubric.txt"), filepath.Join(dir, "scale.txt"),
+			filepath.Join(dir, "gold.csv")}, c.Files(), "Nested includes and examples")
+	}
+	_ = os.WriteFile(path, []byte("messages:\n  - role: robot\n    content: Hi\n"), 0644)
+	_, err = ReadConversation(path)
+	expect.ErrorContains(err, "invalid role")
+}
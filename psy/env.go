@@ -0,0 +1,40 @@
+package psy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// SaveEnv sets a variable in an env file (KEY=value lines), replacing any existing
+// value (retaining the "export" prefix of an "export KEY=value" line), and the other
+// lines. A missing file is created, readable only by the user, because env files
+// usually hold API keys.
+func SaveEnv(path, key, value string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("save env file %s: %w", path, err)
+	}
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+	setting := key + "=" + value
+	i := slices.IndexFunc(lines, func(line string) bool {
+		k, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		return ok && strings.TrimSpace(k) == key
+	})
+	if i < 0 {
+		lines = append(lines, setting)
+	} else if strings.HasPrefix(strings.TrimSpace(lines[i]), "export ") {
+		lines[i] = "export " + setting
+	} else {
+		lines[i] = setting
+	}
+	if err = os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		return fmt.Errorf("save env file %s: %w", path, err)
+	}
+	return nil
+}
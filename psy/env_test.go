@@ -0,0 +1,29 @@
+package psy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveEnv(t *testing.T) {
+	expect := assert.New(t)
+	path := filepath.Join(t.TempDir(), ".env")
+	if !expect.NoError(SaveEnv(path, "MODEL", "ft:gpt-4o-mini:org::abc"), "Missing file") {
+		return
+	}
+	data, _ := os.ReadFile(path)
+	expect.Equal("MODEL=ft:gpt-4o-mini:org::abc\n", string(data))
+	if info, err := os.Stat(path); expect.NoError(err) {
+		expect.Equal(os.FileMode(0600), info.Mode().Perm(), "Private file")
+	}
+
+	expect.NoError(os.WriteFile(path, []byte("# models\nOPENAI_API_KEY=sk-1\nexport MODEL=old\nMODELS=a,b"), 0644))
+	if expect.NoError(SaveEnv(path, "MODEL", "ft:gpt-4o-mini:org::def")) {
+		data, _ = os.ReadFile(path)
+		expect.Equal("# models\nOPENAI_API_KEY=sk-1\nexport MODEL=ft:gpt-4o-mini:org::def\nMODELS=a,b\n", string(data),
+			"Replaced in place")
+	}
+	expect.Error(SaveEnv(filepath.Join(path, "missing", ".env"), "MODEL", "x"), "Invalid path")
+}
@@ -0,0 +1,260 @@
+package psy
+
+import (
+	"fmt"
+	"gpt/openai"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Prompter generates chat prompts for the records of an answer table, from a
+// system message and a prompt template. The template may use any field of the
+// answer record, in addition to the question and (cleaned) answer. The question
+// may be fixed, or looked up for each answer by ID. Alternatively, a multi-turn
+// conversation template may be used instead of the system message and prompt
+// template. A Prompter that samples few-shot examples isn't safe for concurrent use.
+type Prompter struct {
+	System       string            // system message (optional)
+	Template     *Template         // prompt template
+	Conversation *Conversation     // conversation template, instead of the system message and prompt template
+	Question     string            // fixed question (optional)
+	Questions    map[string]string // questions by ID, for lookup (optional)
+	QuestionID   string            // question ID field name, for lookup
+	AnswerField  string            // answer field name
+	examples     []Record          // few-shot examples, with their questions and cleaned answers
+	rng          *rand.Rand        // random source for sampling few-shot examples
+}
+
+// NewPrompter reads the system file, prompt template file, and question file
+// (if any) named by the chat parameters. If the question ID is a name=value
+// pair, the specified question is used for every answer. Otherwise, the named
+// field is used to look up the question for each answer. If a conversation
+// template file is specified instead of the system and prompt files, its
+// few-shot examples are also read.
+func NewPrompter(p ChatParameters) (*Prompter, error) {
+	var err error
+	pr := &Prompter{QuestionID: p.QuestionID, AnswerField: p.AnswerField}
+
+	// Fetch the system template (optional):
+	if p.SystemFile != "" {
+		pr.System, err = ReadTextFile(p.SystemFile)
+		if err != nil {
+			return nil, fmt.Errorf("system file: %w", err)
+		}
+	}
+
+	// Fetch the prompt or conversation template:
+	if p.ConversationFile != "" {
+		if p.SystemFile != "" || p.PromptFile != "" {
+			return nil, fmt.Errorf("conversation %s: system and prompt files not supported", p.ConversationFile)
+		}
+		if pr.Conversation, err = ReadConversation(p.ConversationFile); err != nil {
+			return nil, fmt.Errorf("conversation file: %w", err)
+		}
+	} else if pr.Template, err = ReadTemplate(p.PromptFile); err != nil {
+		return nil, fmt.Errorf("prompt file: %w", err)
+	}
+
+	// Read the (optional) question(s):
+	if p.QuestionFile != "" {
+		if strings.Contains(p.QuestionID, "=") {
+			// Lookup the question by ID:
+			pr.Question, err = ReadCSVField(p.QuestionFile, p.QuestionID, p.QuestionField)
+			if err != nil {
+				return nil, fmt.Errorf("read question: %w", err)
+			}
+		} else {
+			// Read all the questions:
+			pr.Questions, err = ReadCSVFields(p.QuestionFile, p.QuestionID, p.QuestionField)
+			if err != nil {
+				return nil, fmt.Errorf("read questions: %w", err)
+			}
+		}
+	}
+
+	// Read the (optional) few-shot examples, with their questions and answers:
+	if pr.Conversation != nil && pr.Conversation.Examples != nil {
+		e := pr.Conversation.Examples
+		examples, err := ReadCSVTable(e.File)
+		if err != nil {
+			return nil, fmt.Errorf("examples file: %w", err)
+		}
+		if err = pr.Validate(examples, e.File); err != nil {
+			return nil, err
+		}
+		for _, x := range examples.Records {
+			if data, ok := pr.data(x); ok {
+				pr.examples = append(pr.examples, data)
+			}
+		}
+		if len(pr.examples) == 0 {
+			return nil, fmt.Errorf("no examples found in %s", e.File)
+		}
+		seed := e.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		pr.rng = rand.New(rand.NewSource(seed))
+	}
+	return pr, nil
+}
+
+// TemplateFiles returns the paths of the other local files that the templates named
+// by the chat parameters depend on: the few-shot examples file of a conversation, and
+// the files included by the prompt or conversation templates. Templates that can't
+// be read are skipped.
+func TemplateFiles(p ChatParameters) []string {
+	if p.ConversationFile != "" {
+		if c, err := ReadConversation(p.ConversationFile); err == nil {
+			return c.Files()
+		}
+	} else if p.PromptFile != "" {
+		if t, err := ReadTemplate(p.PromptFile); err == nil {
+			return t.Files()
+		}
+	}
+	return nil
+}
+
+// Validate checks that the answer table has the answer field and, if questions
+// are looked up by ID, that every answer's question ID is known.
+func (pr *Prompter) Validate(answers *Table, path string) error {
+	if !answers.HasField(pr.AnswerField) {
+		return fmt.Errorf("answer field %s not found in %s", pr.AnswerField, path)
+	}
+	if pr.Questions == nil {
+		return nil
+	}
+	if !answers.HasField(pr.QuestionID) {
+		return fmt.Errorf("question ID field %s not found in %s", pr.QuestionID, path)
+	}
+	unknownQuestions := make([]string, 0)
+	for _, a := range answers.Records {
+		qid := a[pr.QuestionID]
+		if _, ok := pr.Questions[qid]; !ok {
+			unknownQuestions = append(unknownQuestions, qid)
+		}
+	}
+	if len(unknownQuestions) > 0 {
+		return fmt.Errorf("unknown question IDs in answer file %s: %s", path, strings.Join(unknownQuestions, ", "))
+	}
+	return nil
+}
+
+// Prompt generates the prompt for an answer record. It returns false if the
+// answer is blank, and an error if the template can't be rendered (e.g. it
+// refers to a missing field). Use Messages with a conversation template.
+func (pr *Prompter) Prompt(r Record) (string, bool, error) {
+	if pr.Conversation != nil {
+		return "", false, fmt.Errorf("conversation %s: expecting a single prompt template", pr.Conversation.Path)
+	}
+	data, ok := pr.data(r)
+	if !ok {
+		return "", false, nil
+	}
+	prompt, err := pr.Template.Execute(data)
+	if err != nil {
+		return "", false, err
+	}
+	return prompt, true, nil
+}
+
+// Messages generates the chat messages for an answer record: the system message
+// (if any) and prompt, or the rendered conversation, with its few-shot examples.
+// It returns false if the answer is blank.
+func (pr *Prompter) Messages(r Record) ([]openai.Message, bool, error) {
+	data, ok := pr.data(r)
+	if !ok {
+		return nil, false, nil
+	}
+	if pr.Conversation == nil {
+		prompt, err := pr.Template.Execute(data)
+		if err != nil {
+			return nil, false, err
+		}
+		return ChatMessages(pr.System, prompt), true, nil
+	}
+	messages, err := pr.Conversation.Render(data, pr.shots(data["answer"]))
+	if err != nil {
+		return nil, false, err
+	}
+	return messages, true, nil
+}
+
+// data returns the template data for an answer record: its fields, with the
+// cleaned answer and its question. It returns false if the answer is blank.
+func (pr *Prompter) data(r Record) (Record, bool) {
+	answer := CleanText(r[pr.AnswerField])
+	if answer == "" {
+		return nil, false
+	}
+	data := maps.Clone(r)
+	data["answer"] = answer
+	if _, ok := data["question"]; !ok || pr.Question != "" || pr.Questions != nil {
+		data["question"] = pr.Question
+		if pr.Questions != nil {
+			data["question"] = pr.Questions[r[pr.QuestionID]]
+		}
+	}
+	return data, true
+}
+
+// shots selects the few-shot examples for an answer, excluding any example with
+// the same answer: the first examples, or a random sample of them.
+func (pr *Prompter) shots(answer string) []Record {
+	var candidates []Record
+	for _, x := range pr.examples {
+		if x["answer"] != answer {
+			candidates = append(candidates, x)
+		}
+	}
+	e := pr.Conversation.Examples
+	n := len(candidates)
+	if e.Count > 0 {
+		n = min(n, e.Count)
+	}
+	if !e.Sample {
+		return candidates[:n]
+	}
+	shots := make([]Record, n)
+	for i, j := range pr.rng.Perm(len(candidates))[:n] {
+		shots[i] = candidates[j]
+	}
+	return shots
+}
+
+// Split splits items into training and validation sets, with the specified
+// fraction of items in the validation set. If a stratum function is provided,
+// each stratum (e.g. each score) is split separately, so that both sets have
+// the same distribution of strata. The split is reproducible with the same seed.
+func Split[T any](items []T, ratio float64, stratum func(T) string, seed int64) (train, valid []T) {
+	// Group the items by stratum:
+	var strata []string
+	groups := make(map[string][]T)
+	for _, item := range items {
+		key := ""
+		if stratum != nil {
+			key = stratum(item)
+		}
+		if _, ok := groups[key]; !ok {
+			strata = append(strata, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+	slices.Sort(strata)
+
+	// Shuffle each stratum, and split it:
+	rng := rand.New(rand.NewSource(seed))
+	for _, key := range strata {
+		group := slices.Clone(groups[key])
+		rng.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		n := int(math.Round(float64(len(group)) * ratio))
+		valid = append(valid, group[:n]...)
+		train = append(train, group[n:]...)
+	}
+	return train, valid
+}
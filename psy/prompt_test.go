@@ -0,0 +1,68 @@
+package psy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestPrompter(t *testing.T) {
+	expect := assert.New(t)
+	dir := t.TempDir()
+	promptPath := filepath.Join(dir, "prompt.txt")
+	questionPath := filepath.Join(dir, "questions.csv")
+	_ = os.WriteFile(promptPath, []byte("Q: {{question}}\nA: {{answer}}"), 0644)
+	_ = os.WriteFile(questionPath, []byte("qid,question\nq1,Why?\nq2,How?\n"), 0644)
+	p := ChatParameters{PromptFile: promptPath, QuestionFile: questionPath, QuestionField: "question",
+		QuestionID: "qid", AnswerField: "answer"}
+	pr, err := NewPrompter(p)
+	if !expect.NoError(err) {
+		return
+	}
+	prompt, ok, err := pr.Prompt(Record{"qid": "q2", "answer": " Because. "})
+	expect.NoError(err)
+	expect.True(ok)
+	expect.Equal("Q: How?\nA: Because.", prompt)
+	_, ok, err = pr.Prompt(Record{"qid": "q1", "answer": " "})
+	expect.NoError(err)
+	expect.False(ok, "Blank answer")
+
+	answers := &Table{FieldNames: []string{"qid", "answer"}, Records: []Record{{"qid": "q3", "answer": "x"}}}
+	expect.ErrorContains(pr.Validate(answers, "answers.csv"), "unknown question IDs")
+	answers.Records[0]["qid"] = "q1"
+	expect.NoError(pr.Validate(answers, "answers.csv"))
+
+	p.QuestionID = "qid=q1"
+	pr, err = NewPrompter(p)
+	if expect.NoError(err) {
+		prompt, _, _ = pr.Prompt(Record{"answer": "Yes"})
+		expect.Equal("Q: Why?\nA: Yes", prompt, "Fixed question")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	expect := assert.New(t)
+	var records []Record
+	for i := range 100 {
+		records = append(records, Record{"id": strconv.Itoa(i), "score": strconv.Itoa(i % 4)})
+	}
+	score := func(r Record) string { return r["score"] }
+	train, valid := Split(records, 0.2, score, 7)
+	expect.Len(train, 80)
+	expect.Len(valid, 20)
+	counts := make(map[string]int)
+	for _, r := range valid {
+		counts[r["score"]]++
+	}
+	expect.Equal(map[string]int{"0": 5, "1": 5, "2": 5, "3": 5}, counts, "Stratified by score")
+
+	train2, valid2 := Split(records, 0.2, score, 7)
+	expect.Equal(train, train2, "Reproducible")
+	expect.Equal(valid, valid2, "Reproducible")
+	_, valid3 := Split(records, 0.2, score, 8)
+	expect.NotEqual(valid, valid3, "Seeded")
+	_, valid = Split(records, 0.1, nil, 7)
+	expect.Len(valid, 10, "Unstratified")
+}
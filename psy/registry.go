@@ -0,0 +1,449 @@
+package psy
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gpt/openai"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Job kinds recorded in the Registry.
+const (
+	JobBatch    = "batch"
+	JobFineTune = "fine_tune"
+)
+
+// Job is a local record of a batch or fine-tuning job created by gpt, retaining
+// the information needed to process its results that isn't kept by OpenAI.
+type Job struct {
+	ID         string            `json:"id"`                   // batch ID or fine-tuning job ID
+	Kind       string            `json:"kind"`                 // JobBatch or JobFineTune
+	RunID      string            `json:"runID,omitempty"`      // run ID of a batch in a multi-batch run
+	Status     string            `json:"status,omitempty"`     // last known status
+	Model      string            `json:"model,omitempty"`      // model ID (or base model ID)
+	Endpoint   string            `json:"endpoint,omitempty"`   // batch endpoint
+	FileIDs    []string          `json:"fileIDs,omitempty"`    // uploaded input (or training) file IDs
+	Files      map[string]string `json:"files,omitempty"`      // local file paths and their SHA-256 hashes
+	Parameters *ChatParameters   `json:"parameters,omitempty"` // chat parameters, for chat batches
+	Result     string            `json:"result,omitempty"`     // results file path or fine-tuned model ID
+	CreatedAt  time.Time         `json:"createdAt"`            // creation time
+	UpdatedAt  time.Time         `json:"updatedAt"`            // last update time
+}
+
+// IsDone returns true if the job has reached a terminal status.
+func (j Job) IsDone() bool {
+	switch j.Status {
+	case "completed", "failed", "expired", "cancelled", "succeeded":
+		return true
+	}
+	return false
+}
+
+// Prompt returns the prompt (or conversation) template file of the job's chat
+// parameters, if any.
+func (j Job) Prompt() string {
+	if j.Parameters == nil {
+		return ""
+	}
+	return cmp.Or(j.Parameters.PromptFile, j.Parameters.ConversationFile)
+}
+
+// String provides a simple text display of the Job intended for console output.
+func (j Job) String() string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s", j.ID, j.Kind, j.CreatedAt.Local().Format(time.DateTime),
+		j.Status, j.Model, j.Prompt(), j.Result)
+}
+
+// ChangedTemplates compares the template files of the chat parameters with those
+// recorded for the job, by absolute path and SHA-256 hash, and describes each
+// difference: a recorded file that has changed or is missing, a file that replaces
+// a recorded file with different contents, or a file added or omitted since the
+// job was created. A replacement for a file recorded without a hash (e.g. because
+// it couldn't be read) can't be compared, so it's reported as a difference. The
+// files that the templates depend on (see TemplateFiles) must be unchanged too.
+func (j Job) ChangedTemplates(p ChatParameters) []string {
+	if j.Parameters == nil {
+		return nil
+	}
+	hashes := make(map[string]string)
+	for path, hash := range j.Files {
+		hashes[AbsPath(path)] = hash
+	}
+	r := j.Parameters
+	var changes []string
+	for _, t := range [][2]string{{r.SystemFile, p.SystemFile}, {r.PromptFile, p.PromptFile},
+		{r.ConversationFile, p.ConversationFile}, {r.QuestionFile, p.QuestionFile}} {
+		recorded, current := AbsPath(t[0]), AbsPath(t[1])
+		hash, ok := hashes[recorded]
+		switch {
+		case recorded == current && (current == "" || !ok):
+			continue
+		case current == "":
+			changes = append(changes, recorded+" omitted")
+			continue
+		case recorded == "":
+			changes = append(changes, current+" added")
+			continue
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s replaces %s", current, recorded))
+			continue
+		}
+		data, err := os.ReadFile(current)
+		switch {
+		case err != nil:
+			changes = append(changes, current+" missing")
+		case HashData(data) == hash:
+		case recorded == current:
+			changes = append(changes, current+" changed")
+		default:
+			changes = append(changes, fmt.Sprintf("%s differs from %s", current, recorded))
+		}
+	}
+	files := TemplateFiles(p)
+	for i, path := range files {
+		files[i] = AbsPath(path)
+	}
+	slices.Sort(files)
+	for _, path := range slices.Compact(files) {
+		hash, ok := hashes[path]
+		data, err := os.ReadFile(path)
+		switch {
+		case !ok:
+			changes = append(changes, path+" not recorded")
+		case err != nil:
+			changes = append(changes, path+" missing")
+		case HashData(data) != hash:
+			changes = append(changes, path+" changed")
+		}
+	}
+	return changes
+}
+
+// NewBatchJob creates a Job for a batch, with the hashes of the local files
+// named by the chat parameters (if any), and of the files their templates depend
+// on (see TemplateFiles). The registry is shared by every working directory, so
+// the parameters are recorded with absolute file paths.
+func NewBatchJob(b openai.Batch, p *ChatParameters) Job {
+	if p != nil {
+		abs := p.Abs()
+		p = &abs
+	}
+	j := Job{
+		ID:         b.ID,
+		Kind:       JobBatch,
+		RunID:      b.Metadata["run_id"],
+		Status:     b.Status,
+		Endpoint:   b.Endpoint,
+		FileIDs:    []string{b.InputFileID},
+		Parameters: p,
+		CreatedAt:  time.Unix(b.CreatedAt, 0),
+		UpdatedAt:  time.Now(),
+	}
+	if p != nil {
+		j.Model = p.Model
+		j.Result = p.OutputFile
+		j.Files = HashFiles(slices.Concat([]string{p.InputFile, p.SystemFile, p.PromptFile, p.ConversationFile,
+			p.QuestionFile, p.AnswerFile}, TemplateFiles(*p))...)
+	}
+	return j
+}
+
+// NewFineTuneJob creates a Job for a fine-tuning job, with the hashes of any
+// local training and validation files.
+func NewFineTuneJob(f openai.FineTuneJob, paths ...string) Job {
+	j := Job{
+		ID:        f.ID,
+		Kind:      JobFineTune,
+		Status:    f.Status,
+		Model:     f.Model,
+		FileIDs:   []string{f.TrainingFile},
+		Files:     HashFiles(paths...),
+		Result:    f.FineTunedModel,
+		CreatedAt: time.Unix(f.CreatedAt, 0),
+		UpdatedAt: time.Now(),
+	}
+	if f.ValidationFile != "" {
+		j.FileIDs = append(j.FileIDs, f.ValidationFile)
+	}
+	return j
+}
+
+// HashFiles returns the SHA-256 hashes of the specified local files, keyed by
+// absolute path. Empty paths and unreadable files (e.g. generated files that were
+// never saved) are skipped.
+func HashFiles(paths ...string) map[string]string {
+	hashes := make(map[string]string)
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			hashes[AbsPath(path)] = HashData(data)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	return hashes
+}
+
+// AbsPath returns the absolute form of a local file path, so that the paths
+// recorded in the registry don't depend on the working directory. An empty path
+// is returned as is.
+func AbsPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
+
+// HashData returns the hex-encoded SHA-256 hash of the data.
+func HashData(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// JobFilter selects jobs from the Registry. Zero values match all jobs.
+type JobFilter struct {
+	Kind   string    // job kind
+	Status string    // job status
+	Prompt string    // substring of the prompt file path
+	Since  time.Time // created at or after
+	Until  time.Time // created before
+}
+
+// Match returns true if the job satisfies the filter.
+func (f JobFilter) Match(j Job) bool {
+	switch {
+	case f.Kind != "" && j.Kind != f.Kind:
+		return false
+	case f.Status != "" && j.Status != f.Status:
+		return false
+	case f.Prompt != "" && !strings.Contains(j.Prompt(), f.Prompt):
+		return false
+	case !f.Since.IsZero() && j.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !j.CreatedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Registry is a local record of the jobs created by gpt, saved as a JSON file.
+// Concurrent gpt processes may share the file: Save merges the jobs added or
+// updated with Put into the current file, while holding a lock file.
+type Registry struct {
+	Path string `json:"-"`    // JSON file path
+	Jobs []Job  `json:"jobs"` // jobs, in creation order
+	puts []Job  // jobs added or updated since the registry was opened or saved
+}
+
+// registryLockTimeout is how long Save waits for another process's lock; an
+// older lock file is considered abandoned (e.g. by a crashed process).
+const registryLockTimeout = 10 * time.Second
+
+// DefaultRegistryPath returns the path of the registry file in the user's
+// configuration directory (e.g. ~/.config/gpt/jobs.json).
+func DefaultRegistryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("registry path: %w", err)
+	}
+	return filepath.Join(dir, "gpt", "jobs.json"), nil
+}
+
+// OpenRegistry reads the registry file at the specified path. A missing file
+// is treated as an empty registry.
+func OpenRegistry(path string) (*Registry, error) {
+	r := &Registry{Path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return r, fmt.Errorf("read registry %s: %w", path, err)
+	}
+	if err = json.Unmarshal(data, r); err != nil {
+		return r, fmt.Errorf("read registry %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Save writes the registry file, replacing it atomically. While holding a lock
+// file, the current file is re-read, and the jobs added or updated with Put are
+// merged into it, so that jobs saved by other processes in the meantime aren't lost.
+func (r *Registry) Save() error {
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0755); err != nil {
+		return fmt.Errorf("save registry %s: %w", r.Path, err)
+	}
+	unlock, err := lockFile(r.Path+".lock", registryLockTimeout)
+	if err != nil {
+		return fmt.Errorf("save registry %s: %w", r.Path, err)
+	}
+	defer unlock()
+	current, err := OpenRegistry(r.Path)
+	if err != nil {
+		return fmt.Errorf("save registry %s: %w", r.Path, err)
+	}
+	for _, j := range r.puts {
+		current.Put(j)
+	}
+	r.Jobs, r.puts = current.Jobs, nil
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save registry %s: %w", r.Path, err)
+	}
+	tmp := r.Path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("save registry %s: %w", r.Path, err)
+	}
+	if err = os.Rename(tmp, r.Path); err != nil {
+		return fmt.Errorf("save registry %s: %w", r.Path, err)
+	}
+	return nil
+}
+
+// lockFile creates an exclusive lock file, waiting up to the timeout for another
+// process to release it. A lock file older than the timeout is removed as abandoned.
+// It returns a function that releases the lock.
+func lockFile(path string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if info, e := os.Stat(path); e == nil && time.Since(info.ModTime()) > timeout {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("lock %s: timed out waiting for another process", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Put adds a job to the registry, or updates the job with the same ID. An
+// update retains the original creation time, parameters, and file hashes,
+// if the new job doesn't provide them.
+func (r *Registry) Put(j Job) {
+	r.puts = append(r.puts, j)
+	i := slices.IndexFunc(r.Jobs, func(o Job) bool { return o.ID == j.ID })
+	if i < 0 {
+		r.Jobs = append(r.Jobs, j)
+		return
+	}
+	o := r.Jobs[i]
+	j.CreatedAt = cmp.Or(o.CreatedAt, j.CreatedAt)
+	j.RunID = cmp.Or(j.RunID, o.RunID)
+	j.Model = cmp.Or(j.Model, o.Model)
+	j.Endpoint = cmp.Or(j.Endpoint, o.Endpoint)
+	j.Result = cmp.Or(j.Result, o.Result)
+	if j.Parameters == nil {
+		j.Parameters = o.Parameters
+	}
+	if j.Files == nil {
+		j.Files = o.Files
+	}
+	if j.FileIDs == nil {
+		j.FileIDs = o.FileIDs
+	}
+	r.Jobs[i] = j
+}
+
+// Get returns the job with the specified ID.
+func (r *Registry) Get(id string) (Job, bool) {
+	i := slices.IndexFunc(r.Jobs, func(j Job) bool { return j.ID == id })
+	if i < 0 {
+		return Job{}, false
+	}
+	return r.Jobs[i], true
+}
+
+// Run returns the jobs with the specified run ID or job ID.
+func (r *Registry) Run(id string) []Job {
+	var jobs []Job
+	for _, j := range r.Jobs {
+		if j.ID == id || j.RunID == id {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// List returns the jobs that match the filter, most recent first.
+func (r *Registry) List(f JobFilter) []Job {
+	var jobs []Job
+	for _, j := range r.Jobs {
+		if f.Match(j) {
+			jobs = append(jobs, j)
+		}
+	}
+	slices.SortStableFunc(jobs, func(a, b Job) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return jobs
+}
+
+// Parameters returns the chat parameters recorded for a run, identified by run
+// ID or by the ID of any member batch.
+func (r *Registry) Parameters(id string) (ChatParameters, bool) {
+	j, ok := r.RunJob(id)
+	if !ok {
+		return ChatParameters{}, false
+	}
+	return *j.Parameters, true
+}
+
+// RunJob returns the job whose chat parameters were recorded for a run, identified
+// by run ID or by the ID of any member batch.
+func (r *Registry) RunJob(id string) (Job, bool) {
+	if j, ok := r.Get(id); ok && j.RunID != "" {
+		id = j.RunID
+	}
+	for _, j := range r.Run(id) {
+		if j.Parameters != nil {
+			return j, true
+		}
+	}
+	return Job{}, false
+}
+
+// Output returns the chat parameters of the most recent job whose results were
+// written to the specified output file.
+func (r *Registry) Output(path string) (ChatParameters, bool) {
+	j, ok := r.OutputJob(path)
+	if !ok {
+		return ChatParameters{}, false
+	}
+	return *j.Parameters, true
+}
+
+// OutputJob returns the most recent job whose results were written to the
+// specified output file, compared by absolute path.
+func (r *Registry) OutputJob(path string) (Job, bool) {
+	for _, j := range r.List(JobFilter{}) {
+		if j.Parameters != nil && AbsPath(j.Parameters.OutputFile) == AbsPath(path) {
+			return j, true
+		}
+	}
+	return Job{}, false
+}
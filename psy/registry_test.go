@@ -0,0 +1,165 @@
+package psy
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"gpt/openai"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	expect := assert.New(t)
+	path := filepath.Join(t.TempDir(), "gpt", "jobs.json")
+	r, err := OpenRegistry(path)
+	if !expect.NoError(err, "Missing registry") {
+		return
+	}
+	expect.Empty(r.Jobs)
+
+	// Record a two-batch run and a fine-tuning job:
+	p := &ChatParameters{OutputFile: "scores.csv", PromptFile: "prompts/rubric.txt", Model: "gpt-4o-mini"}
+	day := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	r.Put(NewBatchJob(openai.Batch{ID: "batch_1", Status: "validating", CreatedAt: day.Unix(),
+		Metadata: map[string]string{"run_id": "run_1"}}, p))
+	r.Put(NewBatchJob(openai.Batch{ID: "batch_2", Status: "validating", CreatedAt: day.Unix(),
+		Metadata: map[string]string{"run_id": "run_1"}}, p))
+	r.Put(NewFineTuneJob(openai.FineTuneJob{ID: "ftjob_1", Model: "gpt-4.1-mini", Status: "queued",
+		CreatedAt: day.AddDate(0, 0, 1).Unix()}))
+	r.Put(NewBatchJob(openai.Batch{ID: "batch_1", Status: "completed"}, nil))
+	if !expect.NoError(r.Save()) {
+		return
+	}
+
+	// Read it back:
+	r, err = OpenRegistry(path)
+	if !expect.NoError(err) {
+		return
+	}
+	expect.Len(r.Jobs, 3)
+	j, ok := r.Get("batch_1")
+	if expect.True(ok) {
+		expect.Equal("completed", j.Status, "Updated status")
+		expect.True(j.IsDone())
+		expect.Equal("run_1", j.RunID, "Retained run ID")
+		expect.Equal(AbsPath("scores.csv"), j.Result, "Retained result")
+		expect.Equal(day.Unix(), j.CreatedAt.Unix(), "Retained creation time")
+	}
+	expect.Len(r.Run("run_1"), 2)
+	params, ok := r.Parameters("batch_2")
+	if expect.True(ok) {
+		expect.Equal(AbsPath("scores.csv"), params.OutputFile)
+	}
+	_, ok = r.Parameters("ftjob_1")
+	expect.False(ok, "No parameters")
+	params, ok = r.Output("./scores.csv")
+	if expect.True(ok) {
+		expect.Equal(AbsPath("prompts/rubric.txt"), params.PromptFile)
+	}
+	_, ok = r.Output("other.csv")
+	expect.False(ok, "Unknown output file")
+
+	// Filter the jobs:
+	expect.Len(r.List(JobFilter{}), 3)
+	expect.Equal("ftjob_1", r.List(JobFilter{})[0].ID, "Most recent first")
+	expect.Len(r.List(JobFilter{Kind: JobFineTune}), 1)
+	expect.Len(r.List(JobFilter{Status: "validating"}), 1)
+	expect.Len(r.List(JobFilter{Prompt: "rubric"}), 2)
+	expect.Len(r.List(JobFilter{Since: day.AddDate(0, 0, 1)}), 1)
+	expect.Len(r.List(JobFilter{Until: day.AddDate(0, 0, 1)}), 2)
+}
+
+func TestRegistryConcurrentSaves(t *testing.T) {
+	expect := assert.New(t)
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := OpenRegistry(path)
+			if expect.NoError(err) {
+				r.Put(NewBatchJob(openai.Batch{ID: fmt.Sprintf("batch_%d", i), Status: "validating"}, nil))
+				expect.NoError(r.Save())
+			}
+		}()
+	}
+	wg.Wait()
+	r, err := OpenRegistry(path)
+	if expect.NoError(err) {
+		expect.Len(r.Jobs, 10, "No lost updates")
+	}
+	expect.NoFileExists(path+".lock", "Lock released")
+}
+
+func TestJobChangedTemplates(t *testing.T) {
+	expect := assert.New(t)
+	dir := t.TempDir()
+	prompt, system := filepath.Join(dir, "prompt.txt"), filepath.Join(dir, "system.txt")
+	copied, question := filepath.Join(dir, "copy.txt"), filepath.Join(dir, "question.txt")
+	expect.NoError(os.WriteFile(prompt, []byte("Score {{.essay}}"), 0644))
+	expect.NoError(os.WriteFile(copied, []byte("Score {{.essay}}"), 0644))
+	expect.NoError(os.WriteFile(system, []byte("You are a rater."), 0644))
+	p := ChatParameters{PromptFile: prompt, SystemFile: system, OutputFile: "scores.csv"}
+	r := &Registry{}
+	r.Put(NewBatchJob(openai.Batch{ID: "batch_1", Metadata: map[string]string{"run_id": "run_1"}}, &p))
+	j, ok := r.OutputJob("scores.csv")
+	if !expect.True(ok) {
+		return
+	}
+	expect.Empty(j.ChangedTemplates(p), "Unchanged")
+	expect.Empty(j.ChangedTemplates(ChatParameters{PromptFile: copied, SystemFile: system}), "Same contents")
+	expect.Equal([]string{system + " omitted", question + " added"},
+		j.ChangedTemplates(ChatParameters{PromptFile: prompt, QuestionFile: question}))
+	expect.NoError(os.WriteFile(prompt, []byte("Rate {{.essay}}"), 0644))
+	expect.NoError(os.WriteFile(copied, []byte("Rate {{.essay}}"), 0644))
+	expect.Equal([]string{prompt + " changed"}, j.ChangedTemplates(p))
+	expect.Equal([]string{copied + " differs from " + prompt}, j.ChangedTemplates(ChatParameters{PromptFile: copied,
+		SystemFile: system}))
+	expect.NoError(os.Remove(system))
+	expect.Equal([]string{system + " missing", prompt + " changed"}, j.ChangedTemplates(p))
+	j, ok = r.RunJob("run_1")
+	if expect.True(ok) {
+		expect.Equal("batch_1", j.ID)
+	}
+}
+
+func TestRegistryAbsPaths(t *testing.T) {
+	expect := assert.New(t)
+	dir := t.TempDir()
+	t.Chdir(dir)
+	expect.NoError(os.WriteFile("prompt.txt", []byte("Score {{.essay}}"), 0644))
+	p := &ChatParameters{PromptFile: "prompt.txt", OutputFile: "scores.csv"}
+	r := &Registry{}
+	r.Put(NewBatchJob(openai.Batch{ID: "batch_1"}, p))
+	expect.Equal("scores.csv", p.OutputFile, "Parameters not modified")
+	j, ok := r.OutputJob(filepath.Join(dir, "scores.csv"))
+	if !expect.True(ok, "Absolute output path") {
+		return
+	}
+	expect.Equal(filepath.Join(dir, "prompt.txt"), j.Parameters.PromptFile)
+	expect.Contains(j.Files, filepath.Join(dir, "prompt.txt"))
+	t.Chdir(t.TempDir())
+	_, ok = r.OutputJob("scores.csv")
+	expect.False(ok, "Another directory")
+	expect.NoError(os.WriteFile(filepath.Join(dir, "prompt.txt"), []byte("Rate {{.essay}}"), 0644))
+	expect.Equal([]string{filepath.Join(dir, "prompt.txt") + " changed"}, j.ChangedTemplates(*j.Parameters))
+}
+
+func TestJobChangedTemplateFiles(t *testing.T) {
+	expect := assert.New(t)
+	dir := t.TempDir()
+	path, gold := filepath.Join(dir, "conversation.yaml"), filepath.Join(dir, "gold.csv")
+	expect.NoError(os.WriteFile(path, []byte("messages:\n  - role: user\n    content: \"A: {{answer}}\"\n"+
+		"examples:\n  file: gold.csv\n  completion: \"{{.score}}\"\n"), 0644))
+	expect.NoError(os.WriteFile(gold, []byte("answer,score\nYes,5\n"), 0644))
+	p := ChatParameters{ConversationFile: path, OutputFile: "scores.csv"}
+	j := NewBatchJob(openai.Batch{ID: "batch_1"}, &p)
+	expect.Contains(j.Files, gold, "Examples file hashed")
+	expect.Empty(j.ChangedTemplates(p))
+	expect.NoError(os.WriteFile(gold, []byte("answer,score\nYes,4\n"), 0644))
+	expect.Equal([]string{gold + " changed"}, j.ChangedTemplates(p))
+}
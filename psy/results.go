@@ -0,0 +1,92 @@
+package psy
+
+import (
+	"fmt"
+	"gpt/openai"
+	"slices"
+	"strconv"
+)
+
+// ResponseFields are the field names of a Table of flattened batch responses.
+var ResponseFields = []string{
+	"custom_id", "status_code", "completion", "finish_reason", "prompt_tokens",
+	"completion_tokens", "total_tokens", "error_code", "error_message",
+}
+
+// ResponseTable flattens batch responses into a Table with one Record per
+// custom_id, in custom_id order. See ResponseFields for the field names.
+func ResponseTable(responses map[string]openai.BatchResponseItem) *Table {
+	t := &Table{FieldNames: slices.Clone(ResponseFields)}
+	ids := make([]string, 0, len(responses))
+	for id := range responses {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		o := responses[id].Outcome()
+		t.Records = append(t.Records, Record{
+			"custom_id":         id,
+			"status_code":       strconv.Itoa(o.StatusCode),
+			"completion":        o.Completion,
+			"finish_reason":     o.FinishReason,
+			"prompt_tokens":     strconv.Itoa(o.Usage.PromptTokens),
+			"completion_tokens": strconv.Itoa(o.Usage.CompletionTokens),
+			"total_tokens":      strconv.Itoa(o.Usage.TotalTokens),
+			"error_code":        o.ErrorCode,
+			"error_message":     o.ErrorMessage,
+		})
+	}
+	return t
+}
+
+// MergeResponses adds the completions and selected scores of batch responses to
+// the matching Records of a results Table, joined on the specified field (e.g.
+// "chatID"). The completion of a failed request is its batch error (as formatted
+// by BatchError.Error, e.g. "error batch_expired: expired"). Multiple
+// scores are numbered (e.g. score1, score2). It returns the number of Records
+// without a matching response.
+func MergeResponses(results *Table, on string, responses map[string]openai.BatchResponseItem,
+	scoreField string, sel Selection) int {
+	var maxScoreCount, missing int
+	for _, record := range results.Records {
+		id := record[on]
+		if id == "" {
+			continue
+		}
+		response, ok := responses[id]
+		if !ok {
+			missing++
+			continue
+		}
+		var completion string
+		var scores []float32
+		if response.HasError() {
+			completion = response.Error.Error()
+		} else {
+			completion = response.Completion()
+			scores = SelectScores(completion, sel)
+		}
+		record["completion"] = completion
+		if len(scores) > maxScoreCount {
+			maxScoreCount = len(scores)
+		}
+		for i, score := range scores {
+			field := scoreField
+			if maxScoreCount > 1 {
+				field = fmt.Sprintf("%s%d", scoreField, i+1)
+			}
+			record[field] = fmt.Sprintf("%f", score)
+		}
+	}
+
+	// Add field names to the results table:
+	results.AddField("completion")
+	if maxScoreCount == 1 {
+		results.AddField(scoreField)
+	} else if maxScoreCount > 1 {
+		for i := 1; i <= maxScoreCount; i++ {
+			results.AddField(fmt.Sprintf("%s%d", scoreField, i))
+		}
+	}
+	return missing
+}
@@ -0,0 +1,58 @@
+package psy
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"gpt/openai"
+	"testing"
+)
+
+func testResponses(t *testing.T) map[string]openai.BatchResponseItem {
+	responses := make(map[string]openai.BatchResponseItem)
+	for _, line := range []string{
+		`{"custom_id":"c2","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"Scores: 3 and 4"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":4,"total_tokens":14}}}}`,
+		`{"custom_id":"c1","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"Score: 5"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":3,"total_tokens":13}}}}`,
+		`{"custom_id":"c3","error":{"code":"batch_expired","message":"expired"}}`,
+	} {
+		var item openai.BatchResponseItem
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			t.Fatal(err)
+		}
+		responses[item.CustomID] = item
+	}
+	return responses
+}
+
+func TestResponseTable(t *testing.T) {
+	expect := assert.New(t)
+	table := ResponseTable(testResponses(t))
+	expect.Equal(ResponseFields, table.FieldNames)
+	if expect.Equal(3, table.RecordCount()) {
+		expect.Equal("c1", table.Records[0]["custom_id"], "Sorted by custom_id")
+		expect.Equal("Score: 5", table.Records[0]["completion"])
+		expect.Equal("stop", table.Records[0]["finish_reason"])
+		expect.Equal("13", table.Records[0]["total_tokens"])
+		expect.Equal("batch_expired", table.Records[2]["error_code"])
+	}
+}
+
+func TestMergeResponses(t *testing.T) {
+	expect := assert.New(t)
+	results := &Table{
+		FieldNames: []string{"chatID", "answer"},
+		Records: []Record{
+			{"chatID": "c1", "answer": "a"},
+			{"chatID": "c2", "answer": "b"},
+			{"chatID": "c3", "answer": "c"},
+			{"chatID": "c4", "answer": "d"},
+			{"chatID": "", "answer": "blank"},
+		},
+	}
+	missing := MergeResponses(results, "chatID", testResponses(t), "score", All)
+	expect.Equal(1, missing)
+	expect.Equal([]string{"chatID", "answer", "completion", "score1", "score2"}, results.FieldNames)
+	expect.Equal("3.000000", results.Records[1]["score1"])
+	expect.Equal("4.000000", results.Records[1]["score2"])
+	expect.Equal("error batch_expired: expired", results.Records[2]["completion"])
+	expect.Empty(results.Records[3]["completion"], "Missing response")
+}
@@ -0,0 +1,258 @@
+package psy
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gpt/openai"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Run is a collection of batches that together process a single run of requests.
+// Runs that exceed the batch input limits are split into multiple batches, each
+// tagged with the run_id, run_part, and run_parts metadata.
+type Run struct {
+	ID      string         // run ID (or the batch ID of a single-batch run)
+	Batches []openai.Batch // member batches, ordered by run_part
+}
+
+// RunID returns the run ID of a batch: its run_id metadata, or its batch ID.
+func RunID(b openai.Batch) string {
+	if id := b.Metadata["run_id"]; id != "" {
+		return id
+	}
+	return b.ID
+}
+
+// runParts returns the number of batches in the run of a batch.
+func runParts(b openai.Batch) int {
+	n, err := strconv.Atoi(b.Metadata["run_parts"])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// ReadRun reads the member batches of a run, identified by run ID or by the ID of
+// any member batch (e.g. "batch_abc123"). The run includes any retry batches (see
+// IsRetry), which follow the original batches. The batch list is searched only as
+// far back as the original batches, and not at all for a single batch in progress.
+func ReadRun(ctx context.Context, client *openai.Client, id string) (Run, error) {
+	run := Run{ID: id}
+	parts := 0
+	if strings.HasPrefix(id, "batch_") {
+		b, err := client.ReadBatch(ctx, id)
+		if err != nil {
+			return run, err
+		}
+		run.ID = RunID(b)
+		parts = runParts(b)
+
+		// A batch without a run ID that isn't done yet is a single-batch run without
+		// retries (which require a finished run), so the batch list isn't needed:
+		if b.Metadata["run_id"] == "" && !b.IsDone() {
+			run.Batches = []openai.Batch{b}
+			return run, nil
+		}
+	}
+
+	// Search the batch list (most recent first) for the run's member batches.
+	// Retry batches are newer than the original batches, so the search is
+	// complete once all the original batches have been found.
+	after := ""
+	originals := 0
+	for parts == 0 || originals < parts {
+		batches, hasMore, lastID, err := client.ListBatches(ctx, 100, after)
+		if err != nil {
+			return run, fmt.Errorf("read run %s: %w", run.ID, err)
+		}
+		for _, b := range batches {
+			if RunID(b) == run.ID {
+				run.Batches = append(run.Batches, b)
+				if !IsRetry(b) {
+					originals++
+					parts = runParts(b)
+				}
+			}
+		}
+		if !hasMore || lastID == "" {
+			break
+		}
+		after = lastID
+	}
+	if len(run.Batches) == 0 {
+		return run, fmt.Errorf("read run %s: no batches found", run.ID)
+	}
+	if originals < parts {
+		return run, fmt.Errorf("read run %s: found %d of %d batches", run.ID, originals, parts)
+	}
+	slices.SortFunc(run.Batches, func(a, b openai.Batch) int {
+		pa, _ := strconv.Atoi(a.Metadata["run_part"])
+		pb, _ := strconv.Atoi(b.Metadata["run_part"])
+		return cmp.Or(
+			cmp.Compare(a.Metadata["retry_of"], b.Metadata["retry_of"]),
+			cmp.Compare(pa, pb),
+			cmp.Compare(a.CreatedAt, b.CreatedAt),
+		)
+	})
+	return run, nil
+}
+
+// IsRetry returns true if the batch retries the failed requests of a run.
+func IsRetry(b openai.Batch) bool {
+	return b.Metadata["retry_of"] != ""
+}
+
+// Refresh re-reads the status of the run's member batches.
+func (r *Run) Refresh(ctx context.Context, client *openai.Client) error {
+	for i, b := range r.Batches {
+		if b.IsDone() {
+			continue
+		}
+		updated, err := client.ReadBatch(ctx, b.ID)
+		if err != nil {
+			return fmt.Errorf("read batch %s: %w", b.ID, err)
+		}
+		r.Batches[i] = updated
+	}
+	return nil
+}
+
+// Metadata returns the metadata of the run's first batch, which is shared by all
+// the member batches (except run_part).
+func (r Run) Metadata() map[string]string {
+	if len(r.Batches) == 0 {
+		return nil
+	}
+	return r.Batches[0].Metadata
+}
+
+// RequestCounts returns the request counts, summed over the member batches.
+func (r Run) RequestCounts() openai.RequestCounts {
+	var counts openai.RequestCounts
+	for _, b := range r.Batches {
+		counts.Total += b.RequestCounts.Total
+		counts.Completed += b.RequestCounts.Completed
+		counts.Failed += b.RequestCounts.Failed
+	}
+	return counts
+}
+
+// Duration returns the longest elapsed time of the member batches.
+func (r Run) Duration() time.Duration {
+	var d time.Duration
+	for _, b := range r.Batches {
+		d = max(d, b.Duration())
+	}
+	return d
+}
+
+// IsDone returns true if all the member batches are done.
+func (r Run) IsDone() bool {
+	for _, b := range r.Batches {
+		if !b.IsDone() {
+			return false
+		}
+	}
+	return true
+}
+
+// Progress provides information about the progress of the run.
+func (r Run) Progress() string {
+	if len(r.Batches) == 1 {
+		return r.Batches[0].Progress()
+	}
+	var done int
+	for _, b := range r.Batches {
+		if b.IsDone() {
+			done++
+		}
+	}
+	counts := r.RequestCounts()
+	return fmt.Sprintf("run %s %d/%d batches done, %d total, %d completed, %d failed, %s elapsed", r.ID,
+		done, len(r.Batches), counts.Total, counts.Completed, counts.Failed, r.Duration())
+}
+
+// ReadResponses reads and merges the results of the run's member batches. A
+// successful response is preferred to a failed response for the same request,
+// so that the results of retry batches supersede the original failures. Batches
+// without results (e.g. failed validation) are returned as skipped.
+func (r Run) ReadResponses(ctx context.Context, client *openai.Client) (map[string]openai.BatchResponseItem, []openai.Batch, error) {
+	responses := make(map[string]openai.BatchResponseItem, r.RequestCounts().Total)
+	var skipped []openai.Batch
+	for _, b := range r.Batches {
+		if b.OutputFileID == "" && b.ErrorFileID == "" {
+			skipped = append(skipped, b)
+			continue
+		}
+		_, items, err := client.ReadBatchResponses(ctx, b.ID)
+		if err != nil {
+			return responses, skipped, err
+		}
+		for id, item := range items {
+			if prior, ok := responses[id]; ok && prior.Succeeded() && !item.Succeeded() {
+				continue
+			}
+			responses[id] = item
+		}
+	}
+	return responses, skipped, nil
+}
+
+// FailedRequests identifies the requests of the run that failed or are missing
+// from the responses (e.g. because the batch expired). The request lines are
+// read from the input files of the original batches.
+func (r Run) FailedRequests(ctx context.Context, client *openai.Client, responses map[string]openai.BatchResponseItem) ([][]byte, error) {
+	var failed [][]byte
+	for _, b := range r.Batches {
+		if IsRetry(b) {
+			continue
+		}
+		input, err := client.DownloadFile(ctx, b.InputFileID)
+		if err != nil {
+			return failed, fmt.Errorf("download batch %s input file %s: %w", b.ID, b.InputFileID, err)
+		}
+		for i, line := range bytes.Split(input, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			var item openai.BatchRequestItem
+			if err := json.Unmarshal(line, &item); err != nil {
+				return failed, fmt.Errorf("batch %s input line %d: %w", b.ID, i+1, err)
+			}
+			if response, ok := responses[item.CustomID]; !ok || !response.Succeeded() {
+				failed = append(failed, line)
+			}
+		}
+	}
+	return failed, nil
+}
+
+// SplitBatchInput divides JSONL batch input lines into as few parts as possible,
+// each with at most maxRequests lines and maxBytes bytes.
+func SplitBatchInput(lines [][]byte, maxRequests, maxBytes int) [][]byte {
+	var parts [][]byte
+	var part bytes.Buffer
+	var count int
+	for _, line := range lines {
+		size := len(line) + 1 // including the newline
+		if count > 0 && (count == maxRequests || part.Len()+size > maxBytes) {
+			parts = append(parts, bytes.Clone(part.Bytes()))
+			part.Reset()
+			count = 0
+		}
+		part.Write(line)
+		part.WriteByte('\n')
+		count++
+	}
+	if count > 0 {
+		parts = append(parts, bytes.Clone(part.Bytes()))
+	}
+	return parts
+}
@@ -0,0 +1,37 @@
+package psy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"gpt/openai"
+	"testing"
+)
+
+func TestSplitBatchInput(t *testing.T) {
+	expect := assert.New(t)
+	lines := [][]byte{[]byte("aaaa"), []byte("bbbb"), []byte("cccc"), []byte("dddd"), []byte("eeee")}
+	parts := SplitBatchInput(lines, 2, 100)
+	if expect.Len(parts, 3, "Request limit") {
+		expect.Equal("aaaa\nbbbb\n", string(parts[0]))
+		expect.Equal("eeee\n", string(parts[2]))
+	}
+	parts = SplitBatchInput(lines, 100, 12)
+	if expect.Len(parts, 3, "Byte limit") {
+		expect.Equal("cccc\ndddd\n", string(parts[1]))
+	}
+	expect.Len(SplitBatchInput(lines, 100, 100), 1, "Single part")
+	expect.Len(SplitBatchInput(lines, 100, 3), 5, "Oversized lines")
+	expect.Empty(SplitBatchInput(nil, 100, 100), "No lines")
+}
+
+func TestRunProgress(t *testing.T) {
+	expect := assert.New(t)
+	run := Run{ID: "r1", Batches: []openai.Batch{
+		{ID: "batch_1", Status: "completed", RequestCounts: openai.RequestCounts{Total: 3, Completed: 3}},
+		{ID: "batch_2", Status: "in_progress", RequestCounts: openai.RequestCounts{Total: 2, Completed: 1, Failed: 1}},
+	}}
+	expect.False(run.IsDone())
+	expect.Equal(openai.RequestCounts{Total: 5, Completed: 4, Failed: 1}, run.RequestCounts())
+	expect.Contains(run.Progress(), "run r1 1/2 batches done, 5 total, 4 completed, 1 failed")
+	expect.Equal("r1", RunID(openai.Batch{ID: "batch_3", Metadata: map[string]string{"run_id": "r1"}}))
+	expect.Equal("batch_3", RunID(openai.Batch{ID: "batch_3"}))
+}
@@ -2,6 +2,7 @@ package psy
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -76,3 +77,26 @@ func ParseScore(s string) (float32, error) {
 	score, err := strconv.ParseFloat(s, 32)
 	return float32(score), err
 }
+
+// ParsePreference parses a judge's reply, whose last non-blank line gives the
+// numbers of the best and worst of n responses, separated by a comma (e.g. "2,3").
+// The rest of the reply (e.g. the judge's reasoning) is ignored.
+func ParsePreference(reply string, n int) (best, worst int, err error) {
+	lines := strings.Split(strings.TrimSpace(reply), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	b, w, ok := strings.Cut(last, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("preference %q: expecting best,worst", last)
+	}
+	best, err = strconv.Atoi(strings.TrimSpace(b))
+	if err == nil {
+		worst, err = strconv.Atoi(strings.TrimSpace(w))
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("preference %q: expecting best,worst response numbers", last)
+	}
+	if best < 1 || best > n || worst < 1 || worst > n || best == worst {
+		return 0, 0, fmt.Errorf("preference %q: expecting two different responses from 1 to %d", last, n)
+	}
+	return best, worst, nil
+}
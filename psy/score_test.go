@@ -0,0 +1,23 @@
+package psy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePreference(t *testing.T) {
+	expect := assert.New(t)
+	best, worst, err := ParsePreference("Of the 4 responses, 2 is best and 3 worst.\n\n2, 3\n", 4)
+	if expect.NoError(err) {
+		expect.Equal(2, best)
+		expect.Equal(3, worst)
+	}
+	_, _, err = ParsePreference("Of the 4 responses, 2 is best and 3 worst.", 4)
+	expect.Error(err, "No final line")
+	_, _, err = ParsePreference("2,5", 4)
+	expect.Error(err, "Out of range")
+	_, _, err = ParsePreference("2,2", 4)
+	expect.Error(err, "Same response")
+	_, _, err = ParsePreference("", 4)
+	expect.Error(err, "Empty reply")
+}
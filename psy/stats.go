@@ -0,0 +1,221 @@
+package psy
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Agreement summarizes the agreement between actual (e.g. human) scores and
+// predicted (e.g. model) scores for the same items.
+type Agreement struct {
+	N         int     // number of scored items
+	MAE       float64 // mean absolute error
+	RMSE      float64 // root mean squared error
+	Pearson   float64 // Pearson correlation
+	Spearman  float64 // Spearman rank correlation
+	QWK       float64 // quadratic weighted kappa, of rounded scores
+	Exact     float64 // fraction of rounded scores in exact agreement
+	Labels    []int   // rounded score levels, in order
+	Confusion [][]int // counts of rounded scores: rows are actual, columns are predicted
+}
+
+// NewAgreement measures the agreement between actual and predicted scores,
+// which must be the same length.
+func NewAgreement(actual, predicted []float64) Agreement {
+	a := Agreement{
+		N:        len(actual),
+		MAE:      MAE(actual, predicted),
+		RMSE:     RMSE(actual, predicted),
+		Pearson:  Pearson(actual, predicted),
+		Spearman: Spearman(actual, predicted),
+	}
+	x, y := Round(actual), Round(predicted)
+	a.QWK = QuadraticKappa(x, y)
+	a.Labels, a.Confusion = Confusion(x, y)
+	for i := range x {
+		if x[i] == y[i] {
+			a.Exact++
+		}
+	}
+	if a.N > 0 {
+		a.Exact /= float64(a.N)
+	}
+	return a
+}
+
+// String provides a text display of the confusion matrix intended for console
+// output, with actual scores in rows and predicted scores in columns.
+func (a Agreement) String() string {
+	var sb strings.Builder
+	sb.WriteString("actual\\predicted")
+	for _, l := range a.Labels {
+		fmt.Fprintf(&sb, "\t%d", l)
+	}
+	sb.WriteString("\n")
+	for i, row := range a.Confusion {
+		fmt.Fprintf(&sb, "%d", a.Labels[i])
+		for _, n := range row {
+			fmt.Fprintf(&sb, "\t%d", n)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// MAE returns the mean absolute error of the predicted values.
+func MAE(actual, predicted []float64) float64 {
+	if len(actual) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for i := range actual {
+		sum += math.Abs(predicted[i] - actual[i])
+	}
+	return sum / float64(len(actual))
+}
+
+// RMSE returns the root mean squared error of the predicted values.
+func RMSE(actual, predicted []float64) float64 {
+	if len(actual) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for i := range actual {
+		d := predicted[i] - actual[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(actual)))
+}
+
+// Pearson returns the Pearson correlation coefficient of the values. It returns
+// zero if either set of values is constant.
+func Pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	if n == 0 {
+		return 0
+	}
+	var mx, my float64
+	for i := range x {
+		mx += x[i]
+		my += y[i]
+	}
+	mx, my = mx/n, my/n
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// Spearman returns the Spearman rank correlation coefficient of the values:
+// the Pearson correlation of their ranks, with tied values sharing their
+// average rank.
+func Spearman(x, y []float64) float64 {
+	return Pearson(Ranks(x), Ranks(y))
+}
+
+// Ranks returns the ranks of the values, starting at 1. Tied values share
+// their average rank.
+func Ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case values[a] < values[b]:
+			return -1
+		case values[a] > values[b]:
+			return 1
+		}
+		return 0
+	})
+	ranks := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[order[k]] = rank
+		}
+		i = j + 1
+	}
+	return ranks
+}
+
+// Round rounds the values to the nearest integers.
+func Round(values []float64) []int {
+	rounded := make([]int, len(values))
+	for i, v := range values {
+		rounded[i] = int(math.Round(v))
+	}
+	return rounded
+}
+
+// Confusion returns the confusion matrix of the actual and predicted scores,
+// with its score levels (the union of the actual and predicted scores).
+func Confusion(actual, predicted []int) (labels []int, matrix [][]int) {
+	labels = slices.Concat(actual, predicted)
+	slices.Sort(labels)
+	labels = slices.Compact(labels)
+	matrix = make([][]int, len(labels))
+	for i := range matrix {
+		matrix[i] = make([]int, len(labels))
+	}
+	for i := range actual {
+		r, _ := slices.BinarySearch(labels, actual[i])
+		c, _ := slices.BinarySearch(labels, predicted[i])
+		matrix[r][c]++
+	}
+	return labels, matrix
+}
+
+// QuadraticKappa returns Cohen's kappa with quadratic weights for the actual
+// and predicted scores, over the range of score levels observed. It's 1 for
+// perfect agreement and 0 for chance agreement.
+func QuadraticKappa(actual, predicted []int) float64 {
+	if len(actual) == 0 {
+		return 0
+	}
+	lo := min(slices.Min(actual), slices.Min(predicted))
+	hi := max(slices.Max(actual), slices.Max(predicted))
+	k := hi - lo + 1
+	if k == 1 {
+		return 1
+	}
+	observed := make([][]float64, k)
+	for i := range observed {
+		observed[i] = make([]float64, k)
+	}
+	rowSums := make([]float64, k)
+	colSums := make([]float64, k)
+	for i := range actual {
+		r, c := actual[i]-lo, predicted[i]-lo
+		observed[r][c]++
+		rowSums[r]++
+		colSums[c]++
+	}
+	n := float64(len(actual))
+	var num, den float64
+	for i := range k {
+		for j := range k {
+			w := float64((i-j)*(i-j)) / float64((k-1)*(k-1))
+			num += w * observed[i][j]
+			den += w * rowSums[i] * colSums[j] / n
+		}
+	}
+	if den == 0 {
+		return 1
+	}
+	return 1 - num/den
+}
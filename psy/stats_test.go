@@ -0,0 +1,29 @@
+package psy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAgreement(t *testing.T) {
+	expect := assert.New(t)
+	actual := []float64{1, 2, 3, 4, 2, 3}
+	predicted := []float64{1, 2, 4, 4, 3, 3}
+	a := NewAgreement(actual, predicted)
+	expect.Equal(6, a.N)
+	expect.InDelta(2.0/6, a.MAE, 1e-9)
+	expect.InDelta(0.57735, a.RMSE, 1e-5)
+	expect.InDelta(0.89715, a.Pearson, 1e-5)
+	expect.InDelta(1-12.0/78, a.QWK, 1e-9)
+	expect.InDelta(4.0/6, a.Exact, 1e-9)
+	expect.Equal([]int{1, 2, 3, 4}, a.Labels)
+	expect.Equal([][]int{{1, 0, 0, 0}, {0, 1, 1, 0}, {0, 0, 1, 1}, {0, 0, 0, 1}}, a.Confusion)
+	expect.Contains(a.String(), "3\t0\t0\t1\t1\n")
+
+	expect.Equal([]float64{1, 2.5, 4.5, 6, 2.5, 4.5}, Ranks(actual), "Tied ranks")
+	expect.InDelta(Pearson(Ranks(actual), Ranks(predicted)), a.Spearman, 1e-9)
+	expect.InDelta(1.0, Spearman([]float64{1, 2, 3}, []float64{10, 20, 90}), 1e-9, "Monotonic")
+	expect.Zero(Pearson([]float64{1, 2, 3}, []float64{2, 2, 2}), "Constant")
+	expect.Equal(1.0, QuadraticKappa([]int{2, 3}, []int{2, 3}), "Perfect")
+	expect.Zero(NewAgreement(nil, nil).QWK, "Empty")
+}
@@ -2,6 +2,7 @@ package psy
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/unicode"
@@ -99,6 +100,41 @@ func (t *Table) WriteCSV(path string) error {
 	return nil
 }
 
+// WriteJSONL writes a Table of Records to a JSONL file, one JSON object per Record,
+// with the Table's field/column names as keys.
+func (t *Table) WriteJSONL(path string) error {
+	rows := make([]map[string]string, 0, len(t.Records))
+	for _, record := range t.Records {
+		row := make(map[string]string, len(t.FieldNames))
+		for _, name := range t.FieldNames {
+			row[name] = record[name]
+		}
+		rows = append(rows, row)
+	}
+	return WriteJSONL(path, rows)
+}
+
+// WriteJSONL writes items (e.g. fine-tuning records) to a JSONL file, one JSON
+// object per line.
+func WriteJSONL[T any](path string, items []T) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("write jsonl file %s: %w", path, err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	for _, item := range items {
+		if err = enc.Encode(item); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("write jsonl file %s: %w", path, err)
+		}
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("write jsonl file %s: %w", path, err)
+	}
+	return nil
+}
+
 // ReadCSVTable reads a CSV file and returns a Table of Records.
 func ReadCSVTable(path string) (*Table, error) {
 	table := &Table{
@@ -2,6 +2,8 @@ package psy
 
 import (
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -50,6 +52,18 @@ func TestCSVTable(t *testing.T) {
 	}
 }
 
+func TestWriteJSONL(t *testing.T) {
+	expect := assert.New(t)
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	tbl := &Table{FieldNames: []string{"pid", "essay"}, Records: []Record{{"pid": "1", "essay": "<p>A & B</p>"}, {"pid": "2"}}}
+	if expect.NoError(tbl.WriteJSONL(path)) {
+		data, _ := os.ReadFile(path)
+		expect.Equal("{\"essay\":\"<p>A & B</p>\",\"pid\":\"1\"}\n{\"essay\":\"\",\"pid\":\"2\"}\n", string(data),
+			"Every field, unescaped")
+	}
+	expect.Error(WriteJSONL(filepath.Join(path, "missing.jsonl"), []int{1}), "Invalid path")
+}
+
 func TestCSVFields(t *testing.T) {
 	expect := assert.New(t)
 	fields, err := ReadCSVFields("testdata/table.csv", "pid", "qid")
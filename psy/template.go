@@ -0,0 +1,301 @@
+package psy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// maxIncludeDepth limits nested template includes, to catch include cycles.
+const maxIncludeDepth = 10
+
+// placeholderPattern matches legacy {{name}} placeholders: a bare field name.
+var placeholderPattern = regexp.MustCompile(`\{\{-?\s*([A-Za-z_][A-Za-z0-9_-]*)\s*-?\}\}`)
+
+// templateKeywords are the bare words that Go templates reserve, and so aren't
+// rewritten as legacy placeholders.
+var templateKeywords = []string{"end", "else", "break", "continue", "nil", "true", "false"}
+
+// Template is a prompt template rendered with Go's text/template package, using
+// the fields of a record as its data. Fields are referenced as {{.name}}, or as
+// {{index . "name"}} if the name isn't a Go identifier. Legacy {{name}}
+// placeholders (e.g. {{question}} and {{answer}}) continue to work. A reference
+// to a missing field is an error, except as an argument of default. In addition
+// to the standard functions, the following are available:
+//
+//	default "value" .name   the field value, or "value" if it's blank or missing
+//	include "file.txt"      the contents of a file, relative to the template's directory
+//	include "file.txt" .    a template file, rendered with the same data
+//	lower, upper, trim      text case and whitespace
+type Template struct {
+	Text string // template text
+	Dir  string // directory for included files
+	tmpl *template.Template
+}
+
+// NewTemplate parses the template text. Included files are read relative to
+// the specified directory (the current directory if blank).
+func NewTemplate(name, text, dir string) (*Template, error) {
+	t := &Template{Text: text, Dir: dir}
+	tmpl, err := t.parse(name, text, 0)
+	if err != nil {
+		return nil, err
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// ReadTemplate reads and parses a template file. Included files are read
+// relative to the template file's directory.
+func ReadTemplate(path string) (*Template, error) {
+	text, err := ReadTextFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewTemplate(filepath.Base(path), text, filepath.Dir(path))
+}
+
+// Execute renders the template with the fields of the record.
+func (t *Template) Execute(r Record) (string, error) {
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, withDefaults(t.tmpl, r)); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// withDefaults returns a copy of the template data in which fields that are
+// missing, but provided as arguments of default (e.g. {{default "control"
+// .condition}}), are given nil values, so that default supplies their values
+// rather than the missing fields being an error.
+func withDefaults[V any](tmpl *template.Template, fields map[string]V) map[string]any {
+	data := make(map[string]any, len(fields))
+	for k, v := range fields {
+		data[k] = v
+	}
+	for _, name := range defaultFields(tmpl.Root) {
+		if _, ok := data[name]; !ok {
+			data[name] = nil
+		}
+	}
+	return data
+}
+
+// defaultFields returns the names of the fields that are arguments of default
+// in the parse tree.
+func defaultFields(root parse.Node) []string {
+	var names []string
+	walk(root, func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.PipeNode:
+			// A field piped to default (e.g. {{.condition | default "control"}}):
+			for i, c := range n.Cmds {
+				if name, ok := fieldName(c); ok && i+1 < len(n.Cmds) && isDefault(n.Cmds[i+1]) {
+					names = append(names, name)
+				}
+			}
+		case *parse.CommandNode:
+			if !isDefault(n) {
+				return
+			}
+			for _, arg := range n.Args[1:] {
+				if f, ok := arg.(*parse.FieldNode); ok && len(f.Ident) == 1 {
+					names = append(names, f.Ident[0])
+				}
+			}
+		}
+	})
+	return names
+}
+
+// walk calls the function for each node of the parse tree's actions, and the
+// nodes within them.
+func walk(node parse.Node, fn func(parse.Node)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walk(c, fn)
+		}
+	case *parse.ActionNode:
+		walk(n.Pipe, fn)
+	case *parse.IfNode:
+		walk(n.Pipe, fn)
+		walk(n.List, fn)
+		walk(n.ElseList, fn)
+	case *parse.RangeNode:
+		walk(n.Pipe, fn)
+		walk(n.List, fn)
+		walk(n.ElseList, fn)
+	case *parse.WithNode:
+		walk(n.Pipe, fn)
+		walk(n.List, fn)
+		walk(n.ElseList, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		fn(n)
+		for _, c := range n.Cmds {
+			walk(c, fn)
+		}
+	case *parse.CommandNode:
+		fn(n)
+		for _, arg := range n.Args {
+			walk(arg, fn)
+		}
+	}
+}
+
+// Files returns the paths of the files included by the template with literal
+// names (e.g. {{include "rubric.txt" .}}), including those included by included
+// templates. Files included with computed names can't be identified.
+func (t *Template) Files() []string {
+	return t.includes(t.tmpl.Root, 0)
+}
+
+// includes returns the paths of the files included in the parse tree, at the
+// specified include depth.
+func (t *Template) includes(root parse.Node, depth int) []string {
+	var paths []string
+	walk(root, func(node parse.Node) {
+		c, ok := node.(*parse.CommandNode)
+		if !ok || len(c.Args) < 2 {
+			return
+		}
+		id, ok := c.Args[0].(*parse.IdentifierNode)
+		name, literal := c.Args[1].(*parse.StringNode)
+		if !ok || id.Ident != "include" || !literal {
+			return
+		}
+		path := name.Text
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(t.Dir, path)
+		}
+		paths = append(paths, path)
+		if len(c.Args) < 3 || depth+1 >= maxIncludeDepth {
+			return
+		}
+		if b, err := os.ReadFile(path); err == nil {
+			if tmpl, err := t.parse(filepath.Base(path), string(b), depth+1); err == nil {
+				paths = append(paths, t.includes(tmpl.Root, depth+1)...)
+			}
+		}
+	})
+	return paths
+}
+
+// isDefault reports whether the command is a call of default.
+func isDefault(c *parse.CommandNode) bool {
+	id, ok := c.Args[0].(*parse.IdentifierNode)
+	return ok && id.Ident == "default"
+}
+
+// fieldName returns the name of the field, if the command is a single field
+// reference (e.g. .condition).
+func fieldName(c *parse.CommandNode) (string, bool) {
+	if len(c.Args) != 1 {
+		return "", false
+	}
+	f, ok := c.Args[0].(*parse.FieldNode)
+	if !ok || len(f.Ident) != 1 {
+		return "", false
+	}
+	return f.Ident[0], true
+}
+
+// parse parses the template text, after rewriting its legacy placeholders.
+func (t *Template) parse(name, text string, depth int) (*template.Template, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(t.funcs(depth)).
+		Parse(rewritePlaceholders(text))
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// funcs returns the template functions, with includes at the specified depth.
+func (t *Template) funcs(depth int) template.FuncMap {
+	return template.FuncMap{
+		"default": func(value string, field any) string {
+			if s, ok := field.(string); ok && strings.TrimSpace(s) != "" {
+				return s
+			}
+			return value
+		},
+		"include": func(path string, data ...any) (string, error) {
+			if depth >= maxIncludeDepth {
+				return "", fmt.Errorf("include %s: too many nested includes", path)
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(t.Dir, path)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return "", fmt.Errorf("include %s: %w", path, err)
+			}
+			if len(data) == 0 {
+				return string(b), nil
+			}
+			tmpl, err := t.parse(filepath.Base(path), string(b), depth+1)
+			if err != nil {
+				return "", fmt.Errorf("include %s: %w", path, err)
+			}
+			if fields, ok := data[0].(map[string]any); ok {
+				data[0] = withDefaults(tmpl, fields)
+			}
+			var sb strings.Builder
+			if err = tmpl.Execute(&sb, data[0]); err != nil {
+				return "", err
+			}
+			return sb.String(), nil
+		},
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+	}
+}
+
+// rewritePlaceholders rewrites legacy {{name}} placeholders in the template text
+// as field references: {{.name}}, or {{index . "name"}} for hyphenated names.
+// Template keywords and function names are left alone.
+func rewritePlaceholders(text string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := placeholderPattern.FindStringSubmatch(s)
+		name := m[1]
+		if slices.Contains(templateKeywords, name) || isTemplateFunc(name) {
+			return s
+		}
+		left, right := "{{", "}}"
+		if strings.HasPrefix(s, "{{-") {
+			left = "{{- "
+		}
+		if strings.HasSuffix(s, "-}}") {
+			right = " -}}"
+		}
+		if strings.Contains(name, "-") {
+			return fmt.Sprintf("%sindex . %q%s", left, name, right)
+		}
+		return left + "." + name + right
+	})
+}
+
+// isTemplateFunc reports whether the name is a template function, which may be
+// called without arguments.
+func isTemplateFunc(name string) bool {
+	switch name {
+	case "and", "call", "html", "index", "slice", "js", "len", "not", "or", "print", "printf", "println",
+		"urlquery", "eq", "ge", "gt", "le", "lt", "ne", "default", "include", "lower", "upper", "trim":
+		return true
+	}
+	return false
+}
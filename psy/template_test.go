@@ -0,0 +1,67 @@
+package psy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	expect := assert.New(t)
+	r := Record{"answer": "Yes", "Concept": "Life", "age-group": "adult", "condition": ""}
+
+	tmpl, err := NewTemplate("legacy", "A: {{answer}} ({{ Concept }}, {{age-group}})", "")
+	if expect.NoError(err) {
+		text, err := tmpl.Execute(r)
+		expect.NoError(err)
+		expect.Equal("A: Yes (Life, adult)", text, "Legacy placeholders")
+	}
+
+	tmpl, err = NewTemplate("funcs", `{{if eq .Concept "Life"}}{{lower .Concept}}{{end}} {{default "control" .condition}}`, "")
+	if expect.NoError(err) {
+		text, err := tmpl.Execute(r)
+		expect.NoError(err)
+		expect.Equal("life control", text, "Conditionals and defaults")
+	}
+
+	tmpl, err = NewTemplate("defaults", `{{default "control" .group}}, {{.group | default "none"}}, `+
+		`{{if .answer}}{{default "adult" .age}}{{end}}`, "")
+	if expect.NoError(err) {
+		text, err := tmpl.Execute(r)
+		expect.NoError(err)
+		expect.Equal("control, none, adult", text, "Defaults for missing fields")
+	}
+
+	tmpl, err = NewTemplate("missing", "{{.Phrase}}", "")
+	if expect.NoError(err) {
+		_, err = tmpl.Execute(r)
+		expect.ErrorContains(err, "Phrase", "Missing field")
+	}
+	_, err = NewTemplate("invalid", "{{if .answer}}", "")
+	expect.Error(err, "Unclosed action")
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "rubric.txt"), []byte("Rate {{answer}}."), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "prompt.txt"), []byte(`{{include "rubric.txt" .}} {{include "rubric.txt"}}`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "loop.txt"), []byte(`{{include "loop.txt" .}}`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "group.txt"), []byte(`{{default "control" .group}}`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "nested.txt"), []byte(`{{include "group.txt" .}}`), 0644)
+	tmpl, err = ReadTemplate(filepath.Join(dir, "prompt.txt"))
+	if expect.NoError(err) {
+		text, err := tmpl.Execute(r)
+		expect.NoError(err)
+		expect.Equal("Rate Yes. Rate {{answer}}.", text, "Includes")
+	}
+	tmpl, err = ReadTemplate(filepath.Join(dir, "nested.txt"))
+	if expect.NoError(err) {
+		text, err := tmpl.Execute(r)
+		expect.NoError(err)
+		expect.Equal("control", text, "Included default for a missing field")
+	}
+	tmpl, err = ReadTemplate(filepath.Join(dir, "loop.txt"))
+	if expect.NoError(err) {
+		_, err = tmpl.Execute(r)
+		expect.ErrorContains(err, "too many nested includes")
+	}
+}